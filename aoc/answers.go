@@ -0,0 +1,61 @@
+package aoc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+// Expected holds the known answers for one input. An empty answer is not
+// checked.
+type Expected struct {
+	Part1 string `json:"part1,omitempty"`
+	Part2 string `json:"part2,omitempty"`
+}
+
+// Part returns the expected answer for part 1 or 2.
+func (e Expected) Part(part int) string {
+	if part == 1 {
+		return e.Part1
+	}
+	return e.Part2
+}
+
+// SetPart records the answer for part 1 or 2.
+func (e *Expected) SetPart(part int, answer string) {
+	if part == 1 {
+		e.Part1 = answer
+	} else {
+		e.Part2 = answer
+	}
+}
+
+// Answers are a day's expected answers keyed by input file name, relative to
+// the day's directory.
+type Answers map[string]Expected
+
+// ReadAnswers reads an answers file. A missing file has no answers.
+func ReadAnswers(filename string) (Answers, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return Answers{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	answers := Answers{}
+	if err := json.Unmarshal(data, &answers); err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
+
+// WriteAnswers writes an answers file.
+func WriteAnswers(filename string, answers Answers) error {
+	data, err := json.MarshalIndent(answers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
@@ -0,0 +1,36 @@
+package aoc
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "answers.json")
+
+	answers, err := ReadAnswers(filename)
+	if err != nil || len(answers) != 0 {
+		t.Fatalf("Expected no answers for a missing file, got %v, %v", answers, err)
+	}
+
+	e := Expected{}
+	e.SetPart(1, "3297909")
+	e.SetPart(2, "RLAKF\nline two")
+	answers["input.txt"] = e
+	answers["example.txt"] = Expected{Part1: "2"}
+	if err := WriteAnswers(filename, answers); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := ReadAnswers(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, answers) {
+		t.Errorf("Expected %v, got %v", answers, actual)
+	}
+	if actual["example.txt"].Part(2) != "" || actual["input.txt"].Part(1) != "3297909" {
+		t.Errorf("Unexpected parts %+v", actual)
+	}
+}
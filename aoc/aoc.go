@@ -0,0 +1,51 @@
+// Package aoc defines the interface every day's solution implements so a
+// single runner can parse, solve and time any of them.
+package aoc
+
+import (
+	"adventofcode/imaging"
+	"adventofcode/viz"
+	"errors"
+)
+
+// ErrNotImplemented is returned by parts that have not been solved yet.
+var ErrNotImplemented = errors.New("not implemented")
+
+// Solver solves one day's puzzle. Parse turns the raw puzzle input into
+// whatever the parts work on; each part is given a freshly parsed input so it
+// is free to modify it.
+type Solver interface {
+	Parse(input string) (interface{}, error)
+	Part1(input interface{}) (string, error)
+	Part2(input interface{}) (string, error)
+}
+
+// Output is where a day can show its work while it solves. Days that support
+// it provide a With(Output) Solver; nil fields are not used.
+type Output struct {
+	Screen   *viz.Screen
+	Recorder *imaging.Recorder
+}
+
+// New builds a Solver from a day's parse and part functions.
+func New[T any](parse func(input string) (T, error), part1, part2 func(input T) (string, error)) Solver {
+	return solver[T]{parse, part1, part2}
+}
+
+type solver[T any] struct {
+	parse func(input string) (T, error)
+	part1 func(input T) (string, error)
+	part2 func(input T) (string, error)
+}
+
+func (s solver[T]) Parse(input string) (interface{}, error) {
+	return s.parse(input)
+}
+
+func (s solver[T]) Part1(input interface{}) (string, error) {
+	return s.part1(input.(T))
+}
+
+func (s solver[T]) Part2(input interface{}) (string, error) {
+	return s.part2(input.(T))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"adventofcode/aoc"
+	"adventofcode/day11"
+	"adventofcode/day12"
+	"adventofcode/day13"
+	"adventofcode/day15"
+	"adventofcode/day8"
+)
+
+// showing are the days that can show their work, built for a given output.
+var showing = map[int]func(out aoc.Output) aoc.Solver{
+	8:  day8.With,
+	11: day11.With,
+	12: day12.With,
+	13: day13.With,
+	15: day15.With,
+}
+
+// animations are the days that draw their simulation to a screen, with how
+// many terminal columns their cells take.
+var animations = map[int]int{
+	11: 1,
+	12: 1,
+	13: 2,
+	15: 1,
+}
+
+// pictures are the days that can save an image of their puzzle.
+var pictures = map[int]bool{
+	8:  true,
+	11: true,
+	13: true,
+	15: true,
+}
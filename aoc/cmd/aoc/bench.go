@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const defaultHistory = ".aoc-bench.json"
+
+// benchResult is the cost of one run of a benchmark.
+type benchResult struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+}
+
+// benchRun is every result from one invocation of aoc bench, keyed by
+// benchmark name.
+type benchRun struct {
+	Commit  string                 `json:"commit"`
+	Dirty   bool                   `json:"dirty,omitempty"`
+	Time    time.Time              `json:"time"`
+	Go      string                 `json:"go"`
+	Results map[string]benchResult `json:"results"`
+}
+
+type benchHistory struct {
+	Runs []benchRun `json:"runs"`
+}
+
+func benchName(day, part int) string {
+	if part == 0 {
+		return fmt.Sprintf("day%d/parse", day)
+	}
+	return fmt.Sprintf("day%d/part%d", day, part)
+}
+
+// benchmark times parsing a day's input, for part 0, or parsing it and
+// solving part 1 or 2. Parts are given a fresh input every run, so parsing
+// can't be left out; the parse benchmark shows how much of a part it is.
+// Parts that fail are skipped.
+func benchmark(day, part int, input string) func(b *testing.B) {
+	return func(b *testing.B) {
+		solver := days[day]
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			parsed, err := solver.Parse(input)
+			switch {
+			case err != nil:
+			case part == 1:
+				_, err = solver.Part1(parsed)
+			case part == 2:
+				_, err = solver.Part2(parsed)
+			}
+			if err != nil {
+				b.SkipNow()
+			}
+		}
+	}
+}
+
+func bench(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	historyFile := fs.String("history", defaultHistory, "benchmark history file")
+	against := fs.String("against", "", "commit to compare with (default the latest other commit)")
+	threshold := fs.Float64("threshold", 10, "percent slowdown reported as a regression")
+	part := fs.Int("part", 0, "benchmark only this part (1 or 2), which includes parsing the input")
+	save := fs.Bool("save", true, "record the results in the history file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("invalid part %d", *part)
+	}
+
+	toRun := dayNumbers()
+	if fs.NArg() > 0 {
+		toRun = nil
+		for _, arg := range fs.Args() {
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid day %q", arg)
+			}
+			if _, ok := days[n]; !ok {
+				return fmt.Errorf("no solver for day %d", n)
+			}
+			toRun = append(toRun, n)
+		}
+	}
+	parts := []int{0, 1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+
+	history, err := readHistory(*historyFile)
+	if err != nil {
+		return err
+	}
+
+	commit, dirty := gitCommit()
+	base, ok := history.base(commit, *against)
+	if *against != "" && !ok {
+		return fmt.Errorf("no benchmarks recorded for commit %s", *against)
+	}
+
+	current := benchRun{
+		Commit:  commit,
+		Dirty:   dirty,
+		Time:    time.Now().UTC(),
+		Go:      runtime.Version(),
+		Results: make(map[string]benchResult),
+	}
+
+	for _, n := range toRun {
+		data, err := ioutil.ReadFile(defaultInput(n))
+		if err != nil {
+			return err
+		}
+		for _, p := range parts {
+			br := testing.Benchmark(benchmark(n, p, string(data)))
+			if br.N == 0 {
+				// The part failed or is not implemented.
+				continue
+			}
+			current.Results[benchName(n, p)] = benchResult{
+				NsPerOp:     br.NsPerOp(),
+				BytesPerOp:  br.AllocedBytesPerOp(),
+				AllocsPerOp: br.AllocsPerOp(),
+			}
+		}
+	}
+
+	var baseResults map[string]benchResult
+	if ok {
+		fmt.Fprintf(out, "comparing %s with %s (threshold %.0f%%)\n", describeRun(current), describeRun(base), *threshold)
+		baseResults = base.Results
+	}
+	regressions := printComparison(out, baseResults, current.Results, *threshold)
+
+	if *save {
+		history.add(current)
+		if err := writeHistory(*historyFile, history); err != nil {
+			return err
+		}
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("%d benchmarks regressed by more than %.0f%%", regressions, *threshold)
+	}
+	return nil
+}
+
+func describeRun(r benchRun) string {
+	if r.Dirty {
+		return r.Commit + " (modified)"
+	}
+	return r.Commit
+}
+
+// gitCommit returns the short hash of HEAD and whether the work tree has
+// uncommitted changes.
+func gitCommit() (string, bool) {
+	hash, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "unknown", false
+	}
+	status, _ := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	return strings.TrimSpace(string(hash)), len(status) > 0
+}
+
+func readHistory(filename string) (*benchHistory, error) {
+	h := &benchHistory{}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return h, nil
+}
+
+func writeHistory(filename string, h *benchHistory) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// add records a run, merging it into an earlier run of the same commit so
+// each commit keeps its latest result for every benchmark.
+func (h *benchHistory) add(r benchRun) {
+	for i := range h.Runs {
+		if h.Runs[i].Commit == r.Commit && h.Runs[i].Dirty == r.Dirty {
+			for name, result := range h.Runs[i].Results {
+				if _, ok := r.Results[name]; !ok {
+					r.Results[name] = result
+				}
+			}
+			h.Runs = append(h.Runs[:i], h.Runs[i+1:]...)
+			break
+		}
+	}
+	h.Runs = append(h.Runs, r)
+}
+
+// base finds the run to compare against: the latest run of the commit
+// prefix against, or if that is empty, the latest run of any other commit.
+func (h *benchHistory) base(commit, against string) (benchRun, bool) {
+	for i := len(h.Runs) - 1; i >= 0; i-- {
+		r := h.Runs[i]
+		if against != "" && strings.HasPrefix(r.Commit, against) {
+			return r, true
+		}
+		if against == "" && r.Commit != commit && !r.Dirty {
+			return r, true
+		}
+	}
+	return benchRun{}, false
+}
+
+// printComparison reports the current results next to the base results and
+// returns how many got slower by more than threshold percent.
+func printComparison(w io.Writer, base, current map[string]benchResult, threshold float64) int {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return benchLess(names[i], names[j])
+	})
+
+	regressions := 0
+	for _, name := range names {
+		c := current[name]
+		line := fmt.Sprintf("%-12s %14v %10d B/op %8d allocs/op", name, time.Duration(c.NsPerOp), c.BytesPerOp, c.AllocsPerOp)
+
+		b, ok := base[name]
+		if ok && b.NsPerOp > 0 {
+			delta := float64(c.NsPerOp-b.NsPerOp) / float64(b.NsPerOp) * 100
+			line += fmt.Sprintf("  was %14v %+7.1f%%", time.Duration(b.NsPerOp), delta)
+			if delta > threshold {
+				line += "  REGRESSION"
+				regressions++
+			}
+		}
+		fmt.Fprintln(w, line)
+	}
+	return regressions
+}
+
+// benchLess orders benchmark names by day, then parse before the parts.
+func benchLess(a, b string) bool {
+	da, pa := benchOrder(a)
+	db, pb := benchOrder(b)
+	if da != db {
+		return da < db
+	}
+	return pa < pb
+}
+
+func benchOrder(name string) (day, part int) {
+	if _, err := fmt.Sscanf(name, "day%d/part%d", &day, &part); err != nil {
+		fmt.Sscanf(name, "day%d/parse", &day)
+	}
+	return day, part
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// BenchmarkDays benchmarks the parse and both parts of every day, as in
+// go test -bench 'Days/day16/' ./aoc/cmd/aoc
+func BenchmarkDays(b *testing.B) {
+	for _, n := range dayNumbers() {
+		data, err := ioutil.ReadFile(filepath.Join(dayDir("../../..", n), "input.txt"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, part := range []int{0, 1, 2} {
+			b.Run(benchName(n, part), benchmark(n, part, string(data)))
+		}
+	}
+}
+
+func TestBenchHistory(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "history.json")
+	h, err := readHistory(filename)
+	if err != nil || len(h.Runs) != 0 {
+		t.Fatalf("Expected an empty history, got %+v, %v", h, err)
+	}
+
+	h.add(benchRun{Commit: "aaa", Results: map[string]benchResult{"day1/part1": {NsPerOp: 100}, "day2/part1": {NsPerOp: 50}}})
+	h.add(benchRun{Commit: "bbb", Results: map[string]benchResult{"day1/part1": {NsPerOp: 200}}})
+	h.add(benchRun{Commit: "aaa", Results: map[string]benchResult{"day1/part1": {NsPerOp: 120}}})
+	if err := writeHistory(filename, h); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = readHistory(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Runs) != 2 || h.Runs[1].Commit != "aaa" {
+		t.Fatalf("Expected a rerun to replace the earlier run of its commit, got %+v", h.Runs)
+	}
+	if r := h.Runs[1].Results; r["day1/part1"].NsPerOp != 120 || r["day2/part1"].NsPerOp != 50 {
+		t.Errorf("Expected the rerun to keep results it did not redo, got %+v", r)
+	}
+
+	if base, ok := h.base("aaa", ""); !ok || base.Commit != "bbb" {
+		t.Errorf("Expected to compare with bbb, got %+v", base)
+	}
+	if base, ok := h.base("ccc", "aa"); !ok || base.Commit != "aaa" {
+		t.Errorf("Expected to find aaa by prefix, got %+v", base)
+	}
+	if _, ok := h.base("ccc", "zzz"); ok {
+		t.Error("Expected no run for an unknown commit")
+	}
+}
+
+func TestPrintComparison(t *testing.T) {
+	base := map[string]benchResult{
+		"day2/part1":  {NsPerOp: 1000},
+		"day10/part1": {NsPerOp: 1000},
+	}
+	current := map[string]benchResult{
+		"day2/part1":  {NsPerOp: 1050},
+		"day10/part1": {NsPerOp: 1500},
+		"day10/part2": {NsPerOp: 10},
+		"day10/parse": {NsPerOp: 5},
+	}
+
+	var b bytes.Buffer
+	if n := printComparison(&b, base, current, 10); n != 1 {
+		t.Errorf("Expected 1 regression, got %d", n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	for i, prefix := range []string{"day2/part1", "day10/parse", "day10/part1", "day10/part2"} {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("Expected line %d to be %s, got %q", i, prefix, lines[i])
+		}
+	}
+	if !strings.Contains(lines[2], "+50.0%  REGRESSION") || strings.Contains(lines[0], "REGRESSION") {
+		t.Errorf("Unexpected report:\n%s", b.String())
+	}
+	if strings.Contains(lines[3], "was") {
+		t.Errorf("Expected no comparison without a baseline, got %q", lines[3])
+	}
+}
+
+func TestBenchRejectsInvalidPart(t *testing.T) {
+	history := filepath.Join(t.TempDir(), "history.json")
+	for _, part := range []string{"-1", "3"} {
+		if err := bench([]string{"--history", history, "--part", part, "1"}, ioutil.Discard); err == nil {
+			t.Errorf("Expected an error benchmarking part %s", part)
+		}
+	}
+	if _, err := ioutil.ReadFile(history); err == nil {
+		t.Error("Expected no history to be written")
+	}
+}
@@ -0,0 +1,60 @@
+//go:build ignore
+
+// Gendays writes days.go, registering every dayN package in the repository
+// with the runner.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var dayDirRe = regexp.MustCompile(`^day(\d+)$`)
+
+func main() {
+	dirs, err := filepath.Glob("../../../day*")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var numbers []int
+	for _, dir := range dirs {
+		m := dayDirRe.FindStringSubmatch(filepath.Base(dir))
+		if m == nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.Base(dir)+".go")); err != nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	var b bytes.Buffer
+	fmt.Fprint(&b, "// Code generated by gendays.go; DO NOT EDIT.\n\npackage main\n\nimport (\n\t\"adventofcode/aoc\"\n")
+	for _, n := range numbers {
+		fmt.Fprintf(&b, "\t\"adventofcode/day%d\"\n", n)
+	}
+	fmt.Fprint(&b, ")\n\n// days maps each day to its solver.\nvar days = map[int]aoc.Solver{\n")
+	for _, n := range numbers {
+		fmt.Fprintf(&b, "\t%d: day%d.Solver,\n", n, n)
+	}
+	fmt.Fprint(&b, "}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("days.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
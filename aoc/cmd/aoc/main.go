@@ -0,0 +1,45 @@
+// Command aoc runs the solutions for every day.
+package main
+
+//go:generate go run gendays.go
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: aoc <command> [arguments]
+
+commands:
+  run <day|all> [--part n] [--input file] [--animate] [--fps n] [--record file] [--save file] [--scale n]
+                    solve a day, or every day in order, and time it
+  verify [--update] [day...]
+                    check every day's answers against answers.json
+  bench [--against commit] [--threshold pct] [--part n] [day...]
+                    benchmark each part and compare with an earlier commit
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = run(os.Args[2:], os.Stdout)
+	case "bench":
+		err = bench(os.Args[2:], os.Stdout)
+	case "verify":
+		err = verify(os.Args[2:], os.Stdout)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "aoc %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
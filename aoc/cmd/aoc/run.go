@@ -0,0 +1,215 @@
+package main
+
+import (
+	"adventofcode/aoc"
+	"adventofcode/imaging"
+	"adventofcode/viz"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// result is the outcome of solving one part of a day.
+type result struct {
+	Day    int
+	Part   int
+	Answer string
+	Parse  time.Duration
+	Solve  time.Duration
+	Err    error
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	part := fs.Int("part", 0, "solve only this part (1 or 2)")
+	input := fs.String("input", "", "puzzle input (default dayN/input.txt)")
+	animate := fs.Bool("animate", false, "show the simulation of days that have one")
+	fps := fs.Int("fps", 30, "frames per second when animating")
+	record := fs.String("record", "", "save the animation of one day and part to an asciicast file")
+	save := fs.String("save", "", "save the picture of one day and part to a .png, .svg or .gif file")
+	scale := fs.Int("scale", 8, "pixels per cell when saving a picture")
+
+	// Allow the day before or after the flags.
+	var day string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		day, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if day == "" && fs.NArg() > 0 {
+		day = fs.Arg(0)
+	}
+	if day == "" {
+		return errors.New("usage: aoc run <day|all> [--part n] [--input file] [--animate] [--fps n] [--record file] [--save file] [--scale n]")
+	}
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("invalid part %d", *part)
+	}
+
+	var toRun []int
+	if day == "all" {
+		if *input != "" {
+			return errors.New("--input cannot be used with all")
+		}
+		toRun = dayNumbers()
+	} else {
+		n, err := strconv.Atoi(day)
+		if err != nil {
+			return fmt.Errorf("invalid day %q", day)
+		}
+		if _, ok := days[n]; !ok {
+			return fmt.Errorf("no solver for day %d", n)
+		}
+		toRun = []int{n}
+	}
+
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+
+	var cast io.Writer
+	if *record != "" {
+		if len(toRun) != 1 || len(parts) != 1 {
+			return errors.New("--record needs a single day and --part")
+		}
+		if _, ok := animations[toRun[0]]; !ok {
+			return fmt.Errorf("day %d has no animation", toRun[0])
+		}
+		f, err := os.Create(*record)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		cast = f
+	}
+
+	var recorder *imaging.Recorder
+	if *save != "" {
+		if len(toRun) != 1 || len(parts) != 1 {
+			return errors.New("--save needs a single day and --part")
+		}
+		if _, ok := pictures[toRun[0]]; !ok {
+			return fmt.Errorf("day %d has no picture", toRun[0])
+		}
+		if *fps < 1 {
+			return errors.New("--save needs an --fps of at least 1")
+		}
+		recorder = imaging.NewRecorder(*scale, time.Second/time.Duration(*fps))
+	}
+
+	failed := false
+	for _, n := range toRun {
+		filename := *input
+		if filename == "" {
+			filename = defaultInput(n)
+		}
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range parts {
+			show := aoc.Output{Recorder: recorder}
+			if cellWidth, animated := animations[n]; animated && (*animate || cast != nil) {
+				screenOut := out
+				if !*animate {
+					screenOut = nil
+				}
+				show.Screen = viz.New(screenOut, viz.Options{FPS: *fps, CellWidth: cellWidth, Record: cast})
+			}
+
+			solver := days[n]
+			if show != (aoc.Output{}) {
+				solver = showing[n](show)
+			}
+			r := solve(solver, n, p, string(data))
+			if show.Screen != nil {
+				if err := show.Screen.Close(); err != nil {
+					return err
+				}
+			}
+			printResult(out, r)
+			if recorder != nil && r.Err == nil {
+				if err := recorder.Save(*save); err != nil {
+					return err
+				}
+			}
+			if r.Err != nil && !errors.Is(r.Err, aoc.ErrNotImplemented) {
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return errors.New("some parts failed")
+	}
+	return nil
+}
+
+func dayNumbers() []int {
+	numbers := make([]int, 0, len(days))
+	for n := range days {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+// dayDir is the directory holding a day's inputs, relative to root.
+func dayDir(root string, day int) string {
+	return filepath.Join(root, fmt.Sprintf("day%d", day))
+}
+
+func defaultInput(day int) string {
+	return filepath.Join(dayDir(".", day), "input.txt")
+}
+
+// solve parses input and solves one part of a day with solver, timing each
+// step.
+func solve(solver aoc.Solver, day, part int, input string) result {
+	r := result{Day: day, Part: part}
+
+	start := time.Now()
+	parsed, err := solver.Parse(input)
+	r.Parse = time.Since(start)
+	if err != nil {
+		r.Err = fmt.Errorf("parse: %w", err)
+		return r
+	}
+
+	start = time.Now()
+	if part == 1 {
+		r.Answer, r.Err = solver.Part1(parsed)
+	} else {
+		r.Answer, r.Err = solver.Part2(parsed)
+	}
+	r.Solve = time.Since(start)
+	return r
+}
+
+func printResult(w io.Writer, r result) {
+	label := fmt.Sprintf("Day %2d Part %d:", r.Day, r.Part)
+	timing := fmt.Sprintf("(parse %v, solve %v)", r.Parse.Round(time.Microsecond), r.Solve.Round(time.Microsecond))
+
+	switch {
+	case r.Err != nil:
+		fmt.Fprintf(w, "%s %-20s %s\n", label, r.Err, timing)
+	case strings.Contains(r.Answer, "\n"):
+		fmt.Fprintf(w, "%s %-20s %s\n", label, "", timing)
+		for _, line := range strings.Split(r.Answer, "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	default:
+		fmt.Fprintf(w, "%s %-20s %s\n", label, r.Answer, timing)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"adventofcode/aoc"
+	"adventofcode/viz"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestNoLeakedGoroutines checks that solving a day leaves nothing running,
+// as run all, bench and the tests solve many days in one process.
+func TestNoLeakedGoroutines(t *testing.T) {
+	if testing.Short() {
+		t.Skip("solving every day is slow")
+	}
+
+	for _, n := range dayNumbers() {
+		n := n
+		t.Run(fmt.Sprintf("day%d", n), func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("../../..", defaultInput(n)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			before := runtime.NumGoroutine()
+			for _, p := range []int{1, 2} {
+				solve(days[n], n, p, string(data))
+			}
+			checkGoroutines(t, before)
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken terminal")
+}
+
+// TestNoLeakedGoroutinesWhenDrawFails checks that an animated day stops its
+// program when it gives up because a frame could not be drawn.
+func TestNoLeakedGoroutinesWhenDrawFails(t *testing.T) {
+	for n := range animations {
+		n := n
+		t.Run(fmt.Sprintf("day%d", n), func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("../../..", defaultInput(n)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Not every part draws, but those that do fail on the first frame.
+			before := runtime.NumGoroutine()
+			for _, p := range []int{1, 2} {
+				solver := showing[n](aoc.Output{Screen: viz.New(failingWriter{}, viz.Options{})})
+				solve(solver, n, p, string(data))
+			}
+			checkGoroutines(t, before)
+		})
+	}
+}
+
+// checkGoroutines fails unless the goroutines are back down to before.
+// Goroutines that were let go may take a moment to exit.
+func checkGoroutines(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected %d goroutines after solving, got %d", before, after)
+	}
+}
+
+func TestRunSaveNeedsFPS(t *testing.T) {
+	save := filepath.Join(t.TempDir(), "message.gif")
+	for _, fps := range []string{"0", "-1"} {
+		if err := run([]string{"8", "--part", "2", "--save", save, "--fps", fps}, io.Discard); err == nil {
+			t.Errorf("Expected an error saving with --fps %s", fps)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"adventofcode/aoc"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+const answersFile = "answers.json"
+
+// check is a result compared against its expected answer.
+type check struct {
+	result
+	Input    string
+	Expected string
+}
+
+func (c check) ok() bool {
+	if errors.Is(c.Err, aoc.ErrNotImplemented) {
+		return c.Expected == ""
+	}
+	return c.Err == nil && !c.missing() && c.Answer == c.Expected
+}
+
+// missing reports whether the part was solved but has no answer recorded to
+// check it against.
+func (c check) missing() bool {
+	return c.Err == nil && c.Expected == ""
+}
+
+func (c check) String() string {
+	switch {
+	case c.ok() && errors.Is(c.Err, aoc.ErrNotImplemented):
+		return "not implemented"
+	case c.missing():
+		return fmt.Sprintf("MISSING: no answer recorded, got %q", c.Answer)
+	case c.ok():
+		return "ok"
+	case c.Err != nil:
+		return fmt.Sprintf("FAIL: %v", c.Err)
+	}
+	return fmt.Sprintf("FAIL: expected %q, got %q", c.Expected, c.Answer)
+}
+
+func verify(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	update := fs.Bool("update", false, "record the current answers instead of checking them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	toVerify := dayNumbers()
+	if fs.NArg() > 0 {
+		toVerify = nil
+		for _, arg := range fs.Args() {
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid day %q", arg)
+			}
+			if _, ok := days[n]; !ok {
+				return fmt.Errorf("no solver for day %d", n)
+			}
+			toVerify = append(toVerify, n)
+		}
+	}
+
+	failed, missing := 0, 0
+	for _, n := range toVerify {
+		checks, err := verifyDay(".", n, *update)
+		if err != nil {
+			return err
+		}
+		for _, c := range checks {
+			fmt.Fprintf(out, "Day %2d Part %d %-12s %s\n", c.Day, c.Part, c.Input+":", c)
+			switch {
+			case c.missing():
+				missing++
+			case !c.ok():
+				failed++
+			}
+		}
+	}
+
+	switch {
+	case failed > 0 && missing > 0:
+		return fmt.Errorf("%d answers did not match and %d are not recorded, see verify --update", failed, missing)
+	case failed > 0:
+		return fmt.Errorf("%d answers did not match", failed)
+	case missing > 0:
+		return fmt.Errorf("%d answers are not recorded, see verify --update", missing)
+	}
+	return nil
+}
+
+// verifyDay solves every input listed in a day's answers file, or just
+// input.txt if there are none, and compares the answers. With update, the
+// answers that were solved are recorded instead.
+func verifyDay(root string, day int, update bool) ([]check, error) {
+	dir := dayDir(root, day)
+	answers, err := aoc.ReadAnswers(filepath.Join(dir, answersFile))
+	if err != nil {
+		return nil, fmt.Errorf("day %d: %v", day, err)
+	}
+
+	inputs := make([]string, 0, len(answers))
+	for input := range answers {
+		inputs = append(inputs, input)
+	}
+	if len(inputs) == 0 {
+		inputs = append(inputs, "input.txt")
+	}
+	sort.Strings(inputs)
+
+	var checks []check
+	for _, input := range inputs {
+		data, err := ioutil.ReadFile(filepath.Join(dir, input))
+		if err != nil {
+			return nil, err
+		}
+
+		expected := answers[input]
+		for _, part := range []int{1, 2} {
+			c := check{result: solve(days[day], day, part, string(data)), Input: input, Expected: expected.Part(part)}
+			if update && c.Err == nil {
+				expected.SetPart(part, c.Answer)
+				c.Expected = c.Answer
+			}
+			checks = append(checks, c)
+		}
+		answers[input] = expected
+	}
+
+	if update {
+		if err := aoc.WriteAnswers(filepath.Join(dir, answersFile), answers); err != nil {
+			return nil, err
+		}
+	}
+	return checks, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"adventofcode/aoc"
+	"fmt"
+	"testing"
+)
+
+// TestAnswers checks every day against its recorded answers, catching
+// regressions from changes to shared packages.
+func TestAnswers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("solving every day is slow")
+	}
+
+	for _, n := range dayNumbers() {
+		n := n
+		t.Run(fmt.Sprintf("day%d", n), func(t *testing.T) {
+			checks, err := verifyDay("../../..", n, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range checks {
+				if !c.ok() {
+					t.Errorf("Part %d %s: %s", c.Part, c.Input, c)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	specs := []struct {
+		check   check
+		ok      bool
+		missing bool
+		status  string
+	}{
+		{check{result: result{Answer: "42"}, Expected: "42"}, true, false, "ok"},
+		{check{result: result{Answer: "41"}, Expected: "42"}, false, false, `FAIL: expected "42", got "41"`},
+		{check{result: result{Answer: "42"}}, false, true, `MISSING: no answer recorded, got "42"`},
+		{check{result: result{Err: aoc.ErrNotImplemented}}, true, false, "not implemented"},
+		{check{result: result{Err: aoc.ErrNotImplemented}, Expected: "42"}, false, false, "FAIL: not implemented"},
+	}
+
+	for _, spec := range specs {
+		c := spec.check
+		if c.ok() != spec.ok || c.missing() != spec.missing || c.String() != spec.status {
+			t.Errorf("Expected ok %v, missing %v, %q, got %v, %v, %q", spec.ok, spec.missing, spec.status, c.ok(), c.missing(), c.String())
+		}
+	}
+}
@@ -1,34 +1,27 @@
-package main
+package day1
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"adventofcode/aoc"
+	"adventofcode/utils"
 	"strconv"
 )
 
-func main() {
-	f, err := os.Open("./input.txt")
-	if err != nil {
-		panic(err)
-	}
+var Solver = aoc.New(utils.Ints, part1, part2)
 
+func part1(masses []int) (string, error) {
 	fuelForMass := 0
-  fuelForMassAndFuel := 0
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		mass, err := strconv.Atoi(line)
-		if err != nil {
-			panic(err)
-		}
+	for _, mass := range masses {
 		fuelForMass += getFuelForMass(mass)
-    fuelForMassAndFuel += getFuelForMassAndFuel(mass)
 	}
+	return strconv.Itoa(fuelForMass), nil
+}
 
-	fmt.Printf("Fuel for mass: = %+v\n", fuelForMass)
-	fmt.Printf("Fuel for mass and fuel: = %+v\n", fuelForMassAndFuel)
+func part2(masses []int) (string, error) {
+	fuelForMassAndFuel := 0
+	for _, mass := range masses {
+		fuelForMassAndFuel += getFuelForMassAndFuel(mass)
+	}
+	return strconv.Itoa(fuelForMassAndFuel), nil
 }
 
 func getFuelForMassAndFuel(mass int) int {
@@ -1,11 +1,11 @@
-package main
+package day10
 
 import (
-	"fmt"
-	"io/ioutil"
-	"math"
+	"adventofcode/aoc"
+	"adventofcode/utils"
+	"errors"
 	"sort"
-	"strings"
+	"strconv"
 )
 
 type location struct {
@@ -13,80 +13,106 @@ type location struct {
 	Col int
 }
 
-type directionAndAngle struct {
-	Direction string
-	Angle     float64
+// direction is the smallest whole step from one asteroid towards another.
+// Every asteroid along the same line of sight has the same direction.
+type direction struct {
+	Row int
+	Col int
 }
 
-func (l location) distanceFrom(d location) float64 {
-	return math.Abs(math.Sqrt(math.Pow(float64(d.Col)-float64(l.Col), 2) + math.Pow(float64(d.Row)-float64(l.Row), 2)))
+func directionTo(from, to location) direction {
+	d := direction{to.Row - from.Row, to.Col - from.Col}
+	steps := gcd(abs(d.Row), abs(d.Col))
+	return direction{d.Row / steps, d.Col / steps}
 }
 
-func main() {
-	input, _ := ioutil.ReadFile("./input.txt")
-	asteroidLocations := parseAsteroidLocations(string(input))
+func (l location) distanceFrom(o location) int {
+	return abs(l.Row-o.Row) + abs(l.Col-o.Col)
+}
 
-	visibleAsteroidsByLocation := make(map[location]map[directionAndAngle][]location)
-	for _, l := range asteroidLocations {
-		visibleAsteroidsByLocation[l] = findVisibleAsteroids(l, asteroidLocations)
+// half is 0 for directions from straight up clockwise to just before
+// straight down, and 1 for the rest, remembering rows grow downward.
+func (d direction) half() int {
+	if d.Col > 0 || (d.Col == 0 && d.Row < 0) {
+		return 0
 	}
+	return 1
+}
 
-	var locationWithMostVisible location
-	for l, visible := range visibleAsteroidsByLocation {
-		if len(visible) > len(visibleAsteroidsByLocation[locationWithMostVisible]) {
-			locationWithMostVisible = l
-		}
+// clockwiseBefore reports whether the laser, turning clockwise from straight
+// up, points in direction d before direction o.
+func (d direction) clockwiseBefore(o direction) bool {
+	if d.half() != o.half() {
+		return d.half() < o.half()
 	}
+	// Within a half turn o is clockwise of d when their cross product is
+	// positive.
+	return d.Col*o.Row-d.Row*o.Col > 0
+}
 
-	fmt.Printf("Part 1: %d\n", len(visibleAsteroidsByLocation[locationWithMostVisible]))
+var Solver = aoc.New(parse, part1, part2)
 
-	clockwiseRotationOrder := []string{"U", "UR", "R", "DR", "D", "DL", "L", "UL"}
-	anglesAndAsteroids := visibleAsteroidsByLocation[locationWithMostVisible]
+func parse(input string) ([]location, error) {
+	asteroidLocations := parseAsteroidLocations(utils.Grid(input))
+	if len(asteroidLocations) == 0 {
+		return nil, errors.New("no asteroids")
+	}
+	return asteroidLocations, nil
+}
+
+func part1(asteroidLocations []location) (string, error) {
+	_, visible := findBestLocation(asteroidLocations)
+	return strconv.Itoa(len(visible)), nil
+}
 
-	// Get a list of all of the angles
-	angles := make([]directionAndAngle, 0)
-	for da, _ := range anglesAndAsteroids {
-		angles = append(angles, da)
+func part2(asteroidLocations []location) (string, error) {
+	_, asteroidsByDirection := findBestLocation(asteroidLocations)
+	if len(asteroidLocations) <= 200 {
+		return "", errors.New("fewer than 200 asteroids to vaporize")
 	}
 
-	// Sort the angles by clockwise order
-	sort.Slice(angles, func(i, j int) bool {
-		a := angles[i]
-		b := angles[j]
-		if indexOf(clockwiseRotationOrder, a.Direction) < indexOf(clockwiseRotationOrder, b.Direction) {
-			return true
-		} else if indexOf(clockwiseRotationOrder, a.Direction) > indexOf(clockwiseRotationOrder, b.Direction) {
-			return false
-		} else {
-			return a.Angle < b.Angle
-		}
+	directions := make([]direction, 0, len(asteroidsByDirection))
+	for d := range asteroidsByDirection {
+		directions = append(directions, d)
+	}
+	sort.Slice(directions, func(i, j int) bool {
+		return directions[i].clockwiseBefore(directions[j])
 	})
 
+	// Each turn of the laser vaporizes the closest asteroid left in every
+	// direction.
 	var lastVaporized location
-	for i := 0; i < 200; i++ {
-		var currentAngle directionAndAngle
-		j := i
-		for {
-			currentAngle = angles[j%len(angles)]
-			if len(anglesAndAsteroids[currentAngle]) == 0 {
-				j += 1
-			} else {
-				break
-			}
+	for vaporized, i := 0, 0; vaporized < 200; i = (i + 1) % len(directions) {
+		d := directions[i]
+		if len(asteroidsByDirection[d]) == 0 {
+			continue
 		}
-		lastVaporized = anglesAndAsteroids[currentAngle][0]
-		anglesAndAsteroids[currentAngle] = anglesAndAsteroids[currentAngle][1:]
+		lastVaporized = asteroidsByDirection[d][0]
+		asteroidsByDirection[d] = asteroidsByDirection[d][1:]
+		vaporized++
 	}
 
 	r := (lastVaporized.Col * 100) + lastVaporized.Row
-	fmt.Printf("Part 2: %d\n", r)
+	return strconv.Itoa(r), nil
 }
 
-func parseAsteroidLocations(s string) (asteroidField []location) {
-	lines := strings.Split(s, "\n")
-	for row, line := range lines {
+// findBestLocation returns the asteroid that can see the most others, along
+// with the asteroids it sees grouped by direction.
+func findBestLocation(asteroidLocations []location) (location, map[direction][]location) {
+	var best location
+	var bestVisible map[direction][]location
+	for _, l := range asteroidLocations {
+		if visible := findVisibleAsteroids(l, asteroidLocations); bestVisible == nil || len(visible) > len(bestVisible) {
+			best, bestVisible = l, visible
+		}
+	}
+	return best, bestVisible
+}
+
+func parseAsteroidLocations(grid [][]rune) (asteroidField []location) {
+	for row, line := range grid {
 		for col, object := range line {
-			if string(object) == "#" {
+			if object == '#' {
 				asteroidField = append(asteroidField, location{row, col})
 			}
 		}
@@ -94,47 +120,37 @@ func parseAsteroidLocations(s string) (asteroidField []location) {
 	return
 }
 
-func findVisibleAsteroids(origin location, asteroidLocations []location) map[directionAndAngle][]location {
-	// Find all other asteroids. Group by "slope" (rise/run).
-	asteroidsBySightAngle := make(map[directionAndAngle][]location)
+// findVisibleAsteroids groups every other asteroid by its direction from
+// origin, closest first.
+func findVisibleAsteroids(origin location, asteroidLocations []location) map[direction][]location {
+	asteroidsByDirection := make(map[direction][]location)
 	for _, al := range asteroidLocations {
-		if al.Row == origin.Row && al.Col == origin.Col {
+		if al == origin {
 			continue
 		}
-
-		da := directionAndAngle{}
-		if al.Row <= origin.Row {
-			da.Direction += "U"
-		} else if al.Row >= origin.Row {
-			da.Direction += "D"
-		}
-
-		if al.Col > origin.Col {
-			da.Direction += "R"
-		} else if al.Col < origin.Col {
-			da.Direction += "L"
-		}
-
-		angle := (float64(al.Row) - float64(origin.Row)) / (float64(al.Col) - float64(origin.Col))
-		da.Angle = angle
-
-		asteroidsBySightAngle[da] = append(asteroidsBySightAngle[da], al)
+		d := directionTo(origin, al)
+		asteroidsByDirection[d] = append(asteroidsByDirection[d], al)
 	}
 
-	for _, asteroids := range asteroidsBySightAngle {
+	for _, asteroids := range asteroidsByDirection {
 		sort.Slice(asteroids, func(i, j int) bool {
 			return origin.distanceFrom(asteroids[i]) < origin.distanceFrom(asteroids[j])
 		})
 	}
 
-	return asteroidsBySightAngle
+	return asteroidsByDirection
 }
 
-func indexOf(haystack []string, needle string) int {
-	for i, v := range haystack {
-		if v == needle {
-			return i
-		}
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
 	}
-	return -1
+	return v
 }
@@ -0,0 +1,53 @@
+package day10
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBestLocation(t *testing.T) {
+	asteroids, err := parse(".#..#\n.....\n#####\n....#\n...##\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	best, visible := findBestLocation(asteroids)
+	if best != (location{Row: 4, Col: 3}) || len(visible) != 8 {
+		t.Errorf("Expected 8 visible from (4,3), got %d from %v", len(visible), best)
+	}
+}
+
+func TestDirectionTo(t *testing.T) {
+	specs := []struct {
+		to       location
+		expected direction
+	}{
+		{location{-4, 0}, direction{-1, 0}},
+		{location{6, -9}, direction{2, -3}},
+		{location{0, 7}, direction{0, 1}},
+		{location{-5, -5}, direction{-1, -1}},
+	}
+	for _, spec := range specs {
+		if d := directionTo(location{}, spec.to); d != spec.expected {
+			t.Errorf("%v: expected %v, got %v", spec.to, spec.expected, d)
+		}
+	}
+}
+
+func TestClockwiseOrder(t *testing.T) {
+	expected := []direction{
+		{-1, 0}, {-3, 1}, {-1, 1}, {-1, 3}, {0, 1}, {1, 3}, {1, 1}, {3, 1},
+		{1, 0}, {3, -1}, {1, -1}, {1, -3}, {0, -1}, {-1, -3}, {-1, -1}, {-3, -1},
+	}
+	directions := append([]direction(nil), expected...)
+	rand.New(rand.NewSource(1)).Shuffle(len(directions), func(i, j int) {
+		directions[i], directions[j] = directions[j], directions[i]
+	})
+	sort.Slice(directions, func(i, j int) bool {
+		return directions[i].clockwiseBefore(directions[j])
+	})
+	if !reflect.DeepEqual(directions, expected) {
+		t.Errorf("Expected %v, got %v", expected, directions)
+	}
+}
@@ -1,3 +0,0 @@
-module adventofcode/day10
-
-go 1.15
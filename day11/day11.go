@@ -1,9 +1,16 @@
-package main
+package day11
 
 import (
+	"adventofcode/aoc"
+	"adventofcode/grid"
+	"adventofcode/imaging"
 	"adventofcode/intcode"
+	"adventofcode/ocr"
+	"adventofcode/viz"
 	"fmt"
-	"math"
+	"image/color"
+	"strconv"
+	"strings"
 )
 
 const white = 1
@@ -12,15 +19,22 @@ const black = 0
 const left = 0
 const right = 1
 
-type Location struct {
-	Row int
-	Col int
+var panelColors = map[int]color.Color{
+	black: color.Black,
+	white: color.White,
+}
+
+var arrows = map[grid.Direction]string{
+	grid.Up:    "^",
+	grid.Right: ">",
+	grid.Down:  "v",
+	grid.Left:  "<",
 }
 
 type Robot struct {
-	Grid     map[Location]int
-	Location Location
-	Heading  string
+	Grid     grid.Sparse[int]
+	Location grid.Point
+	Heading  grid.Direction
 }
 
 func (r *Robot) CurrentColor() int {
@@ -37,141 +51,97 @@ func (r *Robot) Paint(c int) {
 func (r *Robot) TurnAndMove(d int) {
 	switch d {
 	case left:
-		switch r.Heading {
-		case "U":
-			r.Heading = "L"
-		case "D":
-			r.Heading = "R"
-		case "L":
-			r.Heading = "D"
-		case "R":
-			r.Heading = "U"
-		}
+		r.Heading = r.Heading.TurnLeft()
 	case right:
-		switch r.Heading {
-		case "U":
-			r.Heading = "R"
-		case "D":
-			r.Heading = "L"
-		case "L":
-			r.Heading = "U"
-		case "R":
-			r.Heading = "D"
-		}
-	}
-	switch r.Heading {
-	case "U":
-		r.Location.Row--
-	case "D":
-		r.Location.Row++
-	case "L":
-		r.Location.Col--
-	case "R":
-		r.Location.Col++
+		r.Heading = r.Heading.TurnRight()
 	}
+	r.Location = r.Location.Move(r.Heading)
 }
 
-func main() {
-	program := intcode.ReadIntcodeProgram("./input.txt")
-
-	// Part 1
-	func() {
-		grid := make(map[Location]int)
-		start := Location{Row: 0, Col: 0}
+var Solver = With(aoc.Output{})
 
-		robot := Robot{
-			Grid:     grid,
-			Location: start,
-			Heading:  "U",
-		}
+// With returns a Solver that shows the robot painting the hull on out.Screen
+// and adds the painted hull to out.Recorder once the robot halts.
+func With(out aoc.Output) aoc.Solver {
+	return aoc.New(parse, func(program intcode.IntcodeProgram) (string, error) {
+		return part1(program, out)
+	}, func(program intcode.IntcodeProgram) (string, error) {
+		return part2(program, out)
+	})
+}
 
-		icc := intcode.NewIntCodeComputer(program)
-		icc.RequestInput = true
-		go icc.Run()
-
-		for {
-			select {
-			case <-icc.InputChannel:
-				icc.InputChannel <- robot.CurrentColor()
-			case color := <-icc.OutputChannel:
-				direction := <-icc.OutputChannel
-				robot.Paint(color)
-				robot.TurnAndMove(direction)
-			case <-icc.DoneChannel:
-				fmt.Printf("Part 1: %d\n", len(robot.Grid))
-				return
-			}
-		}
-	}()
+func parse(input string) (intcode.IntcodeProgram, error) {
+	return intcode.Load(strings.NewReader(input))
+}
 
-	// Part 2
-	func() {
-		grid := make(map[Location]int)
-		start := Location{Row: 0, Col: 0}
+func part1(program intcode.IntcodeProgram, out aoc.Output) (string, error) {
+	robot := Robot{
+		Grid:    make(grid.Sparse[int]),
+		Heading: grid.Up,
+	}
+	if err := robot.Run(program, out); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(len(robot.Grid)), nil
+}
 
-		// Start on the single white panel
-		grid[start] = white
+func part2(program intcode.IntcodeProgram, out aoc.Output) (string, error) {
+	robot := Robot{
+		Grid:    make(grid.Sparse[int]),
+		Heading: grid.Up,
+	}
 
-		robot := Robot{
-			Grid:     grid,
-			Location: start,
-			Heading:  "U",
-		}
+	// Start on the single white panel
+	robot.Paint(white)
+	if err := robot.Run(program, out); err != nil {
+		return "", err
+	}
 
-		icc := intcode.NewIntCodeComputer(program)
-		icc.RequestInput = true
-		go icc.Run()
-
-		for {
-			select {
-			case <-icc.InputChannel:
-				icc.InputChannel <- robot.CurrentColor()
-			case color := <-icc.OutputChannel:
-				direction := <-icc.OutputChannel
-				robot.Paint(color)
-				robot.TurnAndMove(direction)
-			case <-icc.DoneChannel:
-				fmt.Println("Part 2:")
-				// Get bounds of grid
-				minRow := math.MaxInt64
-				maxRow := math.MinInt64
-				minCol := math.MaxInt64
-				maxCol := math.MinInt64
-
-				for loc, _ := range robot.Grid {
-					minRow = min(minRow, loc.Row)
-					maxRow = max(maxRow, loc.Row)
-					minCol = min(minCol, loc.Col)
-					maxCol = max(maxCol, loc.Col)
-				}
+	return ocr.Read[int](robot.Grid, white)
+}
 
-				for row := minRow; row < maxRow+1; row++ {
-					for col := minCol; col < maxCol+1; col++ {
-						c, _ := grid[Location{row, col}]
-						if c == white {
-							fmt.Printf("#")
-						} else {
-							fmt.Printf(" ")
-						}
-					}
-					fmt.Printf("\n")
+// Run paints the hull as directed by the program until it halts, showing its
+// progress on out.
+func (r *Robot) Run(program intcode.IntcodeProgram, out aoc.Output) error {
+	icc := intcode.NewIntCodeComputer(program)
+	icc.RequestInput = true
+	go icc.Run()
+	// Drawing can fail before the program halts.
+	defer icc.Stop()
+
+	for {
+		select {
+		case <-icc.InputChannel:
+			icc.InputChannel <- r.CurrentColor()
+		case color := <-icc.OutputChannel:
+			direction := <-icc.OutputChannel
+			r.Paint(color)
+			r.TurnAndMove(direction)
+			if out.Screen != nil {
+				if err := out.Screen.Draw(r.Frame()); err != nil {
+					return err
 				}
-				return
 			}
+		case <-icc.DoneChannel:
+			if out.Recorder != nil {
+				out.Recorder.Add(imaging.Image[int](r.Grid, r.Grid.Bounds(), imaging.Colors(panelColors, color.Black)))
+			}
+			return nil
 		}
-	}()
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
 	}
-	return b
 }
 
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
+// Frame draws the painted panels and the robot.
+func (r *Robot) Frame() *viz.Frame {
+	f := viz.FromGrid[int](r.Grid, r.Grid.Bounds().Extend(r.Location), func(p grid.Point, color int, ok bool) string {
+		switch {
+		case p == r.Location:
+			return arrows[r.Heading]
+		case color == white:
+			return "#"
+		}
+		return " "
+	})
+	f.Caption = fmt.Sprintf("Painted: %d", len(r.Grid))
+	return f
 }
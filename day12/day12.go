@@ -1,12 +1,21 @@
-package main
+package day12
 
 import (
-	"encoding/json"
+	"adventofcode/aoc"
+	"adventofcode/utils"
+	"adventofcode/viz"
 	"fmt"
-	"io/ioutil"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+var moonNames = []string{"I", "E", "G", "C"}
+
+const viewRadius = 15
+
+var positionRe = regexp.MustCompile(`<x=(-?\d+), y=(-?\d+), z=(-?\d+)>`)
+
 type Position struct {
 	X int
 	Y int
@@ -104,6 +113,29 @@ func (moons Moons) ZDimension() string {
 	return strings.Join(dimZ, ",")
 }
 
+// Frame draws the moons' x and y positions, scaled so the farthest any moon
+// has been from the center, tracked in extent, stays in view.
+func (moons Moons) Frame(step int, extent *int) *viz.Frame {
+	for _, moon := range moons {
+		if abs(moon.Position.X) > *extent {
+			*extent = abs(moon.Position.X)
+		}
+		if abs(moon.Position.Y) > *extent {
+			*extent = abs(moon.Position.Y)
+		}
+	}
+
+	f := viz.NewFrame(2*viewRadius+1, 2*viewRadius+1, " ")
+	f.Set(viewRadius, viewRadius, "+")
+	for i, moon := range moons {
+		row := viewRadius + moon.Position.Y*viewRadius / *extent
+		col := viewRadius + moon.Position.X*viewRadius / *extent
+		f.Set(row, col, moonNames[i%len(moonNames)])
+	}
+	f.Caption = fmt.Sprintf("Step: %d  Energy: %d", step, moons.TotalEnergy())
+	return f
+}
+
 func CalculateGravityDelta(m, o *Moon) {
 	if o.Position.X > m.Position.X {
 		m.GravityDelta.X++
@@ -149,32 +181,55 @@ func (m *Moon) Move() {
 	m.Position.Z += m.Velocity.Z
 }
 
-func (m *Moon) UnmarshalJSON(data []byte) error {
-	m.Position = &Position{}
-	m.Velocity = &Velocity{}
-	m.GravityDelta = &GravityDelta{}
-
-	if err := json.Unmarshal(data, &m.Position); err != nil {
-		return err
+func NewMoons(positions []Position) Moons {
+	moons := make(Moons, len(positions))
+	for i, position := range positions {
+		p := position
+		moons[i] = Moon{
+			Position:     &p,
+			Velocity:     &Velocity{},
+			GravityDelta: &GravityDelta{},
+		}
 	}
-	return nil
+	return moons
+}
+
+var Solver = With(aoc.Output{})
+
+// With returns a Solver whose Part 1 shows the moons moving on out.Screen,
+// looking down on the x-y plane.
+func With(out aoc.Output) aoc.Solver {
+	return aoc.New(parse, func(positions []Position) (string, error) {
+		return part1(positions, out.Screen)
+	}, part2)
 }
 
-func main() {
-	input, _ := ioutil.ReadFile("./input.txt")
-	jsonInput := convertInputToJSON(input)
+func parse(input string) ([]Position, error) {
+	var positions []Position
+	if err := utils.ParseLines(utils.Lines(input), positionRe, &positions); err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
 
-	var moons Moons
-	json.Unmarshal(jsonInput, &moons)
+func part1(positions []Position, screen *viz.Screen) (string, error) {
+	moons := NewMoons(positions)
 
+	extent := 1
 	for i := 0; i < 1000; i++ {
 		moons.Step()
+		if screen != nil {
+			if err := screen.Draw(moons.Frame(i+1, &extent)); err != nil {
+				return "", err
+			}
+		}
 	}
 
-	fmt.Printf("Part 1: %d\n", moons.TotalEnergy())
+	return strconv.Itoa(moons.TotalEnergy()), nil
+}
 
-	// Start over
-	json.Unmarshal(jsonInput, &moons)
+func part2(positions []Position) (string, error) {
+	moons := NewMoons(positions)
 
 	xInitial := moons.XDimension()
 	yInitial := moons.YDimension()
@@ -205,21 +260,7 @@ func main() {
 		}
 	}
 
-	fmt.Printf("Part 2: %+v\n", LCM(xPeriod, yPeriod, zPeriod))
-}
-
-// Quick and dirty. Nothing more.
-func convertInputToJSON(data []byte) []byte {
-	json := "[" + string(data) + "]"
-	json = strings.ReplaceAll(json, "=", ":")
-	json = strings.ReplaceAll(json, "<", "{")
-	json = strings.ReplaceAll(json, ">", "}")
-	json = strings.ReplaceAll(json, "x", "\"x\"")
-	json = strings.ReplaceAll(json, "y", "\"y\"")
-	json = strings.ReplaceAll(json, "z", "\"z\"")
-	json = strings.ReplaceAll(json, "\n", ",")
-	json = strings.ReplaceAll(json, ",]", "]")
-	return []byte(json)
+	return strconv.Itoa(LCM(xPeriod, yPeriod, zPeriod)), nil
 }
 
 func abs(i int) int {
@@ -1,10 +1,15 @@
-package main
+package day13
 
 import (
+	"adventofcode/aoc"
+	"adventofcode/grid"
+	"adventofcode/imaging"
 	"adventofcode/intcode"
+	"adventofcode/viz"
 	"fmt"
-	"math"
-	"time"
+	"image/color"
+	"strconv"
+	"strings"
 )
 
 // Breakout
@@ -19,146 +24,117 @@ const (
 	neutral = 0
 )
 
-type Location struct {
-	Row int
-	Col int
+var tiles = map[int]string{
+	wall:   "🟥",
+	block:  "🟪",
+	paddle: "🟩",
+	ball:   "⚽",
 }
 
-func main() {
-	program := intcode.ReadIntcodeProgram("./input.txt")
-
-	// Part 1
-	func() {
-		grid := make(map[Location]int)
-
-		icc := intcode.NewIntCodeComputer(intcode.CopyIntcodeProgram(program))
-		go icc.Run()
-
-		for {
-			select {
-			case col := <-icc.OutputChannel:
-				row := <-icc.OutputChannel
-				id := <-icc.OutputChannel
-				grid[Location{row, col}] = id
-			case <-icc.DoneChannel:
-				blocks := 0
-				for _, v := range grid {
-					if v == block {
-						blocks++
-					}
-				}
-				fmt.Printf("Part 1: %d\n", blocks)
-				return
-			}
-		}
-	}()
-
-	// Part 2
-	func() {
-		p := intcode.CopyIntcodeProgram(program)
-
-		// Command to start the game
-		p[0] = 2
-
-		grid := make(map[Location]int)
-
-		icc := intcode.NewIntCodeComputer(p)
-		icc.RequestInput = true
-		go icc.Run()
-
-		frames := 0
-		ballCol := 0
-		paddleCol := 0
-		score := 0
-		for {
-			select {
-			case <-icc.InputChannel:
-				if ballCol > paddleCol {
-					icc.InputChannel <- right
-				} else if paddleCol > ballCol {
-					icc.InputChannel <- left
-				} else {
-					icc.InputChannel <- neutral
-				}
-				frames++
-			case col := <-icc.OutputChannel:
-				row := <-icc.OutputChannel
-				id := <-icc.OutputChannel
+var tileColors = map[int]color.Color{
+	wall:   color.RGBA{0xc0, 0x30, 0x30, 0xff},
+	block:  color.RGBA{0x80, 0x40, 0xc0, 0xff},
+	paddle: color.RGBA{0x30, 0xc0, 0x50, 0xff},
+	ball:   color.White,
+}
 
-				if id == ball {
-					ballCol = col
-				}
+var Solver = With(aoc.Output{})
 
-				if id == paddle {
-					paddleCol = col
-				}
+// With returns a Solver whose Part 2 shows the game on out.Screen while it
+// plays, and adds a frame to out.Recorder every time it moves the joystick.
+func With(out aoc.Output) aoc.Solver {
+	return aoc.New(parse, part1, func(p intcode.IntcodeProgram) (string, error) {
+		return part2(p, out)
+	})
+}
 
-				if col == -1 && row == 0 {
-					score = id
-				} else {
-					grid[Location{row, col}] = id
-				}
+func parse(input string) (intcode.IntcodeProgram, error) {
+	return intcode.Load(strings.NewReader(input))
+}
 
-				if frames > 0 {
-					time.Sleep(5 * time.Millisecond)
-					displayGrid(grid)
-					fmt.Printf("\nScore: %d\n\n", score)
-				}
-			case <-icc.DoneChannel:
-				fmt.Printf("Part 2: %d\n", score)
-				return
-			}
+func part1(program intcode.IntcodeProgram) (string, error) {
+	screen := make(grid.Sparse[int])
+
+	icc := intcode.NewIntCodeComputer(program)
+	go icc.Run()
+
+	for {
+		select {
+		case col := <-icc.OutputChannel:
+			row := <-icc.OutputChannel
+			id := <-icc.OutputChannel
+			screen[grid.Point{Row: row, Col: col}] = id
+		case <-icc.DoneChannel:
+			blocks := grid.Count[int](screen, func(id int) bool { return id == block })
+			return strconv.Itoa(blocks), nil
 		}
-	}()
+	}
 }
 
-func displayGrid(g map[Location]int) {
-	// Get bounds of grid
-	minRow := math.MaxInt64
-	maxRow := math.MinInt64
-	minCol := math.MaxInt64
-	maxCol := math.MinInt64
-
-	for loc, _ := range g {
-		minRow = min(minRow, loc.Row)
-		maxRow = max(maxRow, loc.Row)
-		minCol = min(minCol, loc.Col)
-		maxCol = max(maxCol, loc.Col)
-	}
+func part2(p intcode.IntcodeProgram, out aoc.Output) (string, error) {
+	// Command to start the game
+	p[0] = 2
+
+	screen := make(grid.Sparse[int])
+
+	icc := intcode.NewIntCodeComputer(p)
+	icc.RequestInput = true
+	go icc.Run()
+	// Drawing can fail before the program halts.
+	defer icc.Stop()
+
+	frames := 0
+	ballCol := 0
+	paddleCol := 0
+	score := 0
+	for {
+		select {
+		case <-icc.InputChannel:
+			if out.Recorder != nil {
+				out.Recorder.Add(imaging.Image[int](screen, screen.Bounds(), imaging.Colors(tileColors, color.Black)))
+			}
+			if ballCol > paddleCol {
+				icc.InputChannel <- right
+			} else if paddleCol > ballCol {
+				icc.InputChannel <- left
+			} else {
+				icc.InputChannel <- neutral
+			}
+			frames++
+		case col := <-icc.OutputChannel:
+			row := <-icc.OutputChannel
+			id := <-icc.OutputChannel
 
-	// Clear the screen quickly.
-	fmt.Printf("\033[0;0H")
-	for row := minRow; row < maxRow+1; row++ {
-		for col := minCol; col < maxCol+1; col++ {
-			id, ok := g[Location{row, col}]
-
-			switch {
-			case !ok || id == blank:
-				fmt.Printf("  ")
-			case id == wall:
-				fmt.Printf("🟥")
-			case id == block:
-				fmt.Printf("🟪")
-			case id == paddle:
-				fmt.Printf("🟩")
-			case id == ball:
-				fmt.Printf("⚽")
+			if id == ball {
+				ballCol = col
+			}
+
+			if id == paddle {
+				paddleCol = col
 			}
-		}
-		fmt.Printf("\n")
-	}
-}
 
-func min(a, b int) int {
-	if a < b {
-		return a
+			if col == -1 && row == 0 {
+				score = id
+			} else {
+				screen[grid.Point{Row: row, Col: col}] = id
+			}
+
+			if out.Screen != nil && frames > 0 {
+				if err := displayGrid(out.Screen, screen, score); err != nil {
+					return "", err
+				}
+			}
+		case <-icc.DoneChannel:
+			if out.Recorder != nil {
+				out.Recorder.Add(imaging.Image[int](screen, screen.Bounds(), imaging.Colors(tileColors, color.Black)))
+			}
+			return strconv.Itoa(score), nil
+		}
 	}
-	return b
 }
 
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
+func displayGrid(display *viz.Screen, screen grid.Sparse[int], score int) error {
+	f := viz.FromGrid[int](screen, screen.Bounds(), grid.Symbols(tiles, "  "))
+	f.Caption = fmt.Sprintf("Score: %d", score)
+	return display.Draw(f)
 }
@@ -1,12 +1,12 @@
-package main
+package day14
 
 import (
-	"fmt"
-	"io/ioutil"
+	"adventofcode/aoc"
+	"adventofcode/utils"
+	"errors"
 	"math"
 	"regexp"
 	"strconv"
-	"strings"
 )
 
 type Input struct {
@@ -24,25 +24,69 @@ type Reaction struct {
 	Output Output
 }
 
-func main() {
-	input, _ := ioutil.ReadFile("./input.txt")
-	reactions := parseReactions(string(input))
+const ore = "ORE"
 
+var Solver = aoc.New(parse, part1, part2)
+
+func parse(input string) (map[string]Reaction, error) {
 	reactionsByOuputChemical := make(map[string]Reaction)
-	for _, reaction := range reactions {
+	for _, reaction := range parseReactions(utils.Lines(input)) {
 		reactionsByOuputChemical[reaction.Output.Chemical] = reaction
 	}
+	if _, ok := reactionsByOuputChemical["FUEL"]; !ok {
+		return nil, errors.New("no reaction produces FUEL")
+	}
+	return reactionsByOuputChemical, nil
+}
 
-	ore := "ORE"
+func part1(reactionsByOuputChemical map[string]Reaction) (string, error) {
 	target := Output{
 		Amount:   1,
 		Chemical: "FUEL",
 	}
 
+	oreNeeded := calculateOreAmount(reactionsByOuputChemical, target.Chemical, target.Amount)
+	return strconv.Itoa(oreNeeded), nil
+}
+
+func part2(reactionsByOuputChemical map[string]Reaction) (string, error) {
+	// I did the "bottom up" approach for part 1, which makes part 2 trickier.
+	// Taking a more intelligent brute force approach for part 2.
+	// Will try to zero in on the exact amount of fuel needed
+	oreAvailable := 1000000000000
+	fuelAmount := 1
+
+	delta := oreAvailable / 10
+	direction := 1
+
+	for delta != 0 {
+		oreNeeded := calculateOreAmount(reactionsByOuputChemical, "FUEL", fuelAmount)
+		if oreNeeded < oreAvailable {
+			if direction == -1 {
+				direction = 1
+				delta = delta / 10
+			}
+		}
+
+		if oreNeeded > oreAvailable {
+			if direction == 1 {
+				direction = -1
+				delta = delta / 10
+			}
+		}
+
+		fuelAmount += (delta * direction)
+	}
+	return strconv.Itoa(fuelAmount), nil
+}
+
+// calculateOreAmount returns the ore needed to make amountNeeded of chemical
+// starting with nothing left over.
+func calculateOreAmount(reactionsByOuputChemical map[string]Reaction, chemical string, amountNeeded int) int {
 	amountAvailable := make(map[string]int)
 
-	var calculateOreAmount func(chemical string, amountNeeded int) int
-	calculateOreAmount = func(chemical string, amountNeeded int) int {
+	var calculate func(chemical string, amountNeeded int) int
+	calculate = func(chemical string, amountNeeded int) int {
 		if _, ok := amountAvailable[chemical]; !ok {
 			amountAvailable[chemical] = 0
 		}
@@ -65,51 +109,16 @@ func main() {
 
 		oreNeeded := 0
 		for _, input := range producingReaction.Inputs {
-			oreNeeded += calculateOreAmount(input.Chemical, input.Amount*multiplier)
+			oreNeeded += calculate(input.Chemical, input.Amount*multiplier)
 		}
 		return oreNeeded
 	}
 
-	oreNeeded := calculateOreAmount(target.Chemical, target.Amount)
-	fmt.Printf("Part 1: %d\n", oreNeeded)
-
-	// Part 2
-	// I did the "bottom up" approach for part 1, which makes part 2 trickier.
-	// Taking a more intelligent brute force approach for part 2.
-	// Will try to zero in on the exact amount of fuel needed
-	oreNeeded = 0
-	oreAvailable := 1000000000000
-	fuelAmount := 1
-
-	delta := oreAvailable / 10
-	direction := 1
-
-	for delta != 0 {
-		// Reset inbetween runs
-		amountAvailable = make(map[string]int)
-		oreNeeded = calculateOreAmount("FUEL", fuelAmount)
-		if oreNeeded < oreAvailable {
-			if direction == -1 {
-				direction = 1
-				delta = delta / 10
-			}
-		}
-
-		if oreNeeded > oreAvailable {
-			if direction == 1 {
-				direction = -1
-				delta = delta / 10
-			}
-		}
-
-		fuelAmount += (delta * direction)
-	}
-	fmt.Printf("Part 2: %d\n", fuelAmount)
+	return calculate(chemical, amountNeeded)
 }
 
-func parseReactions(data string) []Reaction {
+func parseReactions(lines []string) []Reaction {
 	amountAndChemicalRe := regexp.MustCompile(`(\d+)\s(\w+)`)
-	lines := strings.Split(data, "\n")
 
 	reactions := make([]Reaction, 0)
 	for _, line := range lines {
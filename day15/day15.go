@@ -1,9 +1,17 @@
-package main
+package day15
 
 import (
+	"adventofcode/aoc"
+	"adventofcode/grid"
+	"adventofcode/imaging"
 	"adventofcode/intcode"
+	"adventofcode/search"
+	"adventofcode/viz"
+	"errors"
 	"fmt"
-	"math"
+	"image/color"
+	"strconv"
+	"strings"
 )
 
 // Movement
@@ -16,6 +24,13 @@ const (
 
 const oxygenated = 9
 
+var statusColors = map[int]color.Color{
+	wall:       color.RGBA{0x40, 0x40, 0x40, 0xff},
+	moved:      color.RGBA{0xe0, 0xe0, 0xe0, 0xff},
+	tank:       color.RGBA{0xe0, 0x30, 0x30, 0xff},
+	oxygenated: color.RGBA{0x30, 0x80, 0xe0, 0xff},
+}
+
 // Status codes
 const (
 	wall  = 0
@@ -23,26 +38,83 @@ const (
 	tank  = 2
 )
 
-type Location struct {
-	Row int
-	Col int
+var headings = map[int]grid.Direction{
+	north: grid.Up,
+	south: grid.Down,
+	east:  grid.Right,
+	west:  grid.Left,
+}
+
+var Solver = With(aoc.Output{})
+
+// With returns a Solver that shows the droid exploring and the oxygen
+// spreading on out.Screen, and adds a frame of the area to out.Recorder every
+// minute while the oxygen spreads.
+func With(out aoc.Output) aoc.Solver {
+	return aoc.New(parse, func(program intcode.IntcodeProgram) (string, error) {
+		return part1(program, out.Screen)
+	}, func(program intcode.IntcodeProgram) (string, error) {
+		return part2(program, out)
+	})
+}
+
+func parse(input string) (intcode.IntcodeProgram, error) {
+	return intcode.Load(strings.NewReader(input))
 }
 
-func main() {
-	program := intcode.ReadIntcodeProgram("./input.txt")
+func part1(program intcode.IntcodeProgram, screen *viz.Screen) (string, error) {
+	area, oxygenTankLocation, err := explore(program, screen)
+	if err != nil {
+		return "", err
+	}
+
+	toTank, _ := search.BFS[grid.Point](openArea(area), grid.Point{}, func(p grid.Point) bool { return p == oxygenTankLocation })
+	return strconv.Itoa(len(toTank) - 1), nil
+}
+
+func part2(program intcode.IntcodeProgram, out aoc.Output) (string, error) {
+	area, oxygenTankLocation, err := explore(program, out.Screen)
+	if err != nil {
+		return "", err
+	}
+
+	// Oxygen spreads one layer of locations further every minute.
+	layers := search.FloodFill[grid.Point](openArea(area), oxygenTankLocation)
+	if out.Screen != nil || out.Recorder != nil {
+		for minute, layer := range layers {
+			for _, l := range layer {
+				area[l] = oxygenated
+			}
+			if out.Recorder != nil {
+				out.Recorder.Add(imaging.Image[int](area, area.Bounds(), imaging.Colors(statusColors, color.Black)))
+			}
+			if out.Screen != nil {
+				if err := displayGrid(out.Screen, area, oxygenTankLocation, fmt.Sprintf("Minutes: %d", minute)); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+	return strconv.Itoa(len(layers) - 1), nil
+}
 
-	// Part 1
-	grid := make(map[Location]int)
-	var oxygenTankLocation Location
+// explore drives the droid through the whole area, returning the status of
+// every location it found and where the oxygen tank is.
+func explore(program intcode.IntcodeProgram, screen *viz.Screen) (grid.Sparse[int], grid.Point, error) {
+	area := make(grid.Sparse[int])
+	var oxygenTankLocation grid.Point
+	foundTank := false
 
 	path := make([]int, 0)
-	currentLocation := Location{Row: 0, Col: 0}
+	currentLocation := grid.Point{}
 	direction := north
-	stepsToTank := 0
 
-	icc := intcode.NewIntCodeComputer(intcode.CopyIntcodeProgram(program))
+	icc := intcode.NewIntCodeComputer(program)
 	icc.RequestInput = true
 	go icc.Run()
+	// The droid is still waiting to be told where to go once the whole area
+	// has been explored.
+	defer icc.Stop()
 
 	for direction != -2 {
 		select {
@@ -52,64 +124,65 @@ func main() {
 			switch statusCode {
 			case wall:
 				wallLocation := applyDirection(currentLocation, direction)
-				grid[wallLocation] = wall
+				area[wallLocation] = wall
 			case moved:
 				currentLocation = applyDirection(currentLocation, direction)
-				if _, ok := grid[currentLocation]; !ok {
-					grid[currentLocation] = moved
+				if _, ok := area[currentLocation]; !ok {
+					area[currentLocation] = moved
 					path = append(path, direction)
 				}
 			case tank:
 				currentLocation = applyDirection(currentLocation, direction)
 				oxygenTankLocation = currentLocation
-				grid[currentLocation] = tank
+				foundTank = true
+				area[currentLocation] = tank
 				path = append(path, direction)
-				stepsToTank = len(path)
 			}
 
-			direction, path = getDirection(grid, currentLocation, path)
+			direction, path = getDirection(area, currentLocation, path)
+			if screen != nil {
+				if err := displayGrid(screen, area, currentLocation, fmt.Sprintf("Explored: %d", len(area))); err != nil {
+					return nil, oxygenTankLocation, err
+				}
+			}
 		case <-icc.DoneChannel:
-			return
+			return nil, oxygenTankLocation, errors.New("droid halted before exploring the area")
 		}
 	}
 
-	fmt.Printf("Part 1 (Steps to tank): %d\n", stepsToTank)
-
-	// Part 2
-	currentLocation = oxygenTankLocation
-	minutes := 0
-	var oxygenate func(l Location, minutes int) int
-	oxygenate = func(l Location, currentMinutes int) int {
-		switch grid[l] {
-		case wall, oxygenated:
-			return currentMinutes - 1
-		case moved, tank:
-			grid[l] = oxygenated
-			for _, oxygenateLocation := range []Location{applyDirection(l, north), applyDirection(l, south), applyDirection(l, east), applyDirection(l, west)} {
-				minutes = max(minutes, oxygenate(oxygenateLocation, currentMinutes+1))
-			}
-		}
-		return minutes
+	if !foundTank {
+		return nil, oxygenTankLocation, errors.New("no oxygen tank found")
 	}
+	return area, oxygenTankLocation, nil
+}
 
-	minutes = oxygenate(oxygenTankLocation, 0)
-	fmt.Printf("Part 2 (Minutes to full oxygenation): %+v\n", minutes)
+// openArea is the graph of explored locations the droid can move between.
+func openArea(area grid.Sparse[int]) search.Graph[grid.Point] {
+	return search.GraphFunc[grid.Point](func(p grid.Point) []grid.Point {
+		var neighbors []grid.Point
+		for _, n := range p.Neighbors4() {
+			if status, ok := area[n]; ok && status != wall {
+				neighbors = append(neighbors, n)
+			}
+		}
+		return neighbors
+	})
 }
 
-func getDirection(grid map[Location]int, l Location, path []int) (int, []int) {
+func getDirection(area grid.Sparse[int], l grid.Point, path []int) (int, []int) {
 	northDirection := applyDirection(l, north)
 	southDirection := applyDirection(l, south)
 	eastDirection := applyDirection(l, east)
 	westDirection := applyDirection(l, west)
 
 	direction := -1
-	if _, ok := grid[northDirection]; !ok {
+	if _, ok := area[northDirection]; !ok {
 		direction = north
-	} else if _, ok := grid[southDirection]; !ok {
+	} else if _, ok := area[southDirection]; !ok {
 		direction = south
-	} else if _, ok := grid[eastDirection]; !ok {
+	} else if _, ok := area[eastDirection]; !ok {
 		direction = east
-	} else if _, ok := grid[westDirection]; !ok {
+	} else if _, ok := area[westDirection]; !ok {
 		direction = west
 	}
 
@@ -137,74 +210,30 @@ func getReverseDirection(direction int) int {
 	return -1
 }
 
-func applyDirection(l Location, direction int) Location {
-	newLocation := Location{
-		Row: l.Row,
-		Col: l.Col,
-	}
-	switch direction {
-	case north:
-		newLocation.Row--
-	case south:
-		newLocation.Row++
-	case east:
-		newLocation.Col++
-	case west:
-		newLocation.Col--
-	}
-	return newLocation
+func applyDirection(l grid.Point, direction int) grid.Point {
+	return l.Move(headings[direction])
 }
 
-func displayGrid(g map[Location]int, l Location) {
-	// Get bounds of grid
-	minRow := math.MaxInt64
-	maxRow := math.MinInt64
-	minCol := math.MaxInt64
-	maxCol := math.MinInt64
-
-	for loc, _ := range g {
-		minRow = min(minRow, loc.Row)
-		maxRow = max(maxRow, loc.Row)
-		minCol = min(minCol, loc.Col)
-		maxCol = max(maxCol, loc.Col)
-	}
-
-	// Clear the screen quickly.
-	fmt.Printf("\033[0;0H")
-	for row := minRow; row < maxRow+1; row++ {
-		for col := minCol; col < maxCol+1; col++ {
-			id, ok := g[Location{row, col}]
-			if l.Row == row && l.Col == col {
-				fmt.Print("*")
-			} else if row == 0 && col == 0 {
-				fmt.Printf("O")
-			} else if !ok {
-				fmt.Printf(" ")
-			} else if id == moved {
-				fmt.Printf(".")
-			} else if id == tank {
-				fmt.Printf("T")
-			} else if ok && id == wall {
-				fmt.Printf("#")
-			} else if ok && id == oxygenated {
-				fmt.Printf("9")
-			}
+func displayGrid(screen *viz.Screen, area grid.Sparse[int], l grid.Point, caption string) error {
+	f := viz.FromGrid[int](area, area.Bounds().Extend(l), func(p grid.Point, id int, ok bool) string {
+		switch {
+		case p == l:
+			return "*"
+		case p == grid.Point{}:
+			return "O"
+		case !ok:
+			return " "
+		case id == moved:
+			return "."
+		case id == tank:
+			return "T"
+		case id == wall:
+			return "#"
+		case id == oxygenated:
+			return "o"
 		}
-		fmt.Printf("\n")
-	}
-	fmt.Println("--------------------")
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
+		return " "
+	})
+	f.Caption = caption
+	return screen.Draw(f)
 }
@@ -1,21 +1,21 @@
-package main
+package day16
 
 import (
+	"adventofcode/aoc"
+	"adventofcode/utils"
 	"fmt"
-	"io/ioutil"
-	"strconv"
-	"strings"
 )
 
-func main() {
-	input, _ := ioutil.ReadFile("./input.txt")
-	originalSignal := make([]int, 0)
-	basePattern := []int{0, 1, 0, -1}
+var Solver = aoc.New(utils.Digits, part1, part2)
+
+// messageLength is how many digits of the signal make up the message.
+const messageLength = 8
 
-	for _, sDigit := range strings.Trim(string(input), "\n") {
-		digit, _ := strconv.Atoi(string(sDigit))
-		originalSignal = append(originalSignal, digit)
+func part1(originalSignal []int) (string, error) {
+	if len(originalSignal) < messageLength {
+		return "", fmt.Errorf("signal of %d digits is shorter than the message", len(originalSignal))
 	}
+	basePattern := []int{0, 1, 0, -1}
 
 	verifySignal := make([]int, len(originalSignal))
 	copy(verifySignal, originalSignal)
@@ -24,22 +24,24 @@ func main() {
 		verifySignal = runPhase(verifySignal, basePattern)
 	}
 
-	output := ""
-	for _, digit := range verifySignal[:8] {
-		output += fmt.Sprintf("%d", digit)
-	}
-
-	fmt.Printf("Part 1: %+v\n", output)
+	return digitsToString(verifySignal[:messageLength]), nil
+}
 
-	//	Part 2
+func part2(originalSignal []int) (string, error) {
+	if len(originalSignal) < messageLength {
+		return "", fmt.Errorf("signal of %d digits is shorter than the message", len(originalSignal))
+	}
 	realSignal := make([]int, 0)
 	for i := 0; i < 10000; i++ {
 		realSignal = append(realSignal, originalSignal...)
 	}
 
 	offset := realSignal[0]*1000000 + realSignal[1]*100000 + realSignal[2]*10000 + realSignal[3]*1000 + realSignal[4]*100 + realSignal[5]*10 + realSignal[6]
+	if offset < len(realSignal)/2 || offset+messageLength > len(realSignal) {
+		return "", fmt.Errorf("message offset %d is not in the second half of the signal", offset)
+	}
 
-	verifySignal = make([]int, len(realSignal))
+	verifySignal := make([]int, len(realSignal))
 	copy(verifySignal, realSignal)
 	for i := 0; i < 100; i++ {
 		verifySignal[len(realSignal)-1] = realSignal[len(realSignal)-1]
@@ -50,7 +52,15 @@ func main() {
 		realSignal = verifySignal
 	}
 
-	fmt.Printf("realSignal[:8] = %+v\n", realSignal[offset:offset+8])
+	return digitsToString(realSignal[offset : offset+messageLength]), nil
+}
+
+func digitsToString(digits []int) string {
+	output := ""
+	for _, digit := range digits {
+		output += fmt.Sprintf("%d", digit)
+	}
+	return output
 }
 
 func runPhase(signal []int, basePattern []int) []int {
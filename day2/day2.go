@@ -1,59 +1,44 @@
-package main
+package day2
 
 import (
+	"adventofcode/aoc"
 	"adventofcode/intcode"
-	"fmt"
+	"errors"
+	"strconv"
+	"strings"
 )
 
-type intcodeProgram []int
+var Solver = aoc.New(parse, part1, part2)
 
-const (
-	add  = 1
-	mult = 2
-	halt = 99
-)
-
-type instruction struct {
-	Opcode      int
-	Param1      int
-	Param2      int
-	Destination int
+func parse(input string) (intcode.IntcodeProgram, error) {
+	return intcode.Load(strings.NewReader(input))
 }
 
-func main() {
-	intcodeProgram := intcode.ReadIntcodeProgram("./input.txt")
-
-	r := part1(intcode.CopyIntcodeProgram(intcodeProgram))
-	fmt.Printf("Result: = %d\n", r[0])
-
-	noun, verb := part2(intcode.CopyIntcodeProgram(intcodeProgram))
-	fmt.Printf("Noun: %d Verb: %d Computed: %d\n", noun, verb, 100*noun+verb)
-}
-
-func part1(p intcode.IntcodeProgram) intcodeProgram {
+func part1(p intcode.IntcodeProgram) (string, error) {
 	p[1] = 12
 	p[2] = 2
 
-	icc := intcode.NewIntCodeComputer(p)
-
-	go icc.Run()
-	<-icc.DoneChannel
-	return icc.Program
+	return strconv.Itoa(executeIntcodeProgram(p)[0]), nil
 }
 
-func part2(p intcode.IntcodeProgram) (int, int) {
+func part2(p intcode.IntcodeProgram) (string, error) {
 	for noun := 0; noun <= 99; noun++ {
 		for verb := 0; verb <= 99; verb++ {
 			candidate := intcode.CopyIntcodeProgram(p)
 			candidate[1] = noun
 			candidate[2] = verb
-			icc := intcode.NewIntCodeComputer(candidate)
-			go icc.Run()
-			<-icc.DoneChannel
-			if icc.Program[0] == 19690720 {
-				return noun, verb
+			if executeIntcodeProgram(candidate)[0] == 19690720 {
+				return strconv.Itoa(100*noun + verb), nil
 			}
 		}
 	}
-	return -1, -1
+	return "", errors.New("no noun and verb produce 19690720")
+}
+
+func executeIntcodeProgram(p []int) []int {
+	icc := intcode.NewIntCodeComputer(p)
+
+	go icc.Run()
+	<-icc.DoneChannel
+	return icc.Program
 }
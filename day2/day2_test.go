@@ -1,4 +1,4 @@
-package main
+package day2
 
 import (
 	"fmt"
@@ -43,7 +43,7 @@ func TestGravityAssistDay2(t *testing.T) {
 	}
 }
 
-func Equal(a, b intcodeProgram) bool {
+func Equal(a, b []int) bool {
 	if len(a) != len(b) {
 		return false
 	}
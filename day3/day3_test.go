@@ -1,108 +1,39 @@
-package main
+package day3
 
 import (
 	"testing"
 )
 
-func TestWireBounds(t *testing.T) {
-	w := wire{
-		id: 1,
-		path: []instruction{
-			instruction{
-				Direction: "U",
-				Steps:     3,
-			},
-			instruction{
-				Direction: "D",
-				Steps:     5,
-			},
-			instruction{
-				Direction: "L",
-				Steps:     10,
-			},
-			instruction{
-				Direction: "R",
-				Steps:     15,
-			},
-		},
+func TestEverything(t *testing.T) {
+	specs := []struct {
+		input    string
+		distance string
+		delay    string
+	}{
+		{"R8,U5,L5,D3\nU7,R6,D4,L4", "6", "30"},
+		{"R75,D30,R83,U83,L12,D49,R71,U7,L72\nU62,R66,U55,R34,D71,R55,D58,R83", "159", "610"},
+		{"R98,U47,R26,D63,R33,U87,L62,D20,R33,U53,R51\nU98,R91,D20,R16,D67,R40,U7,R15,U6,R7", "135", "410"},
+		{"R8,U5,L5,D3\nU7,R6,D4,L4\nL2,U4,R4", "4", "12"},
 	}
 
-	up, down, left, right := w.bounds()
-	if up != 3 {
-		t.Errorf("Expected up to be 3, got %d", up)
-	}
-	if down != -2 {
-		t.Errorf("Expected down to be -2, got %d", down)
-	}
-	if left != -10 {
-		t.Errorf("Expected up to be 3, got %d", left)
-	}
-	if right != 5 {
-		t.Errorf("Expected up to be 3, got %d", right)
+	for _, spec := range specs {
+		ws, err := parse(spec.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if distance, err := part1(ws); err != nil || distance != spec.distance {
+			t.Errorf("Expected distance %s, got %s (%v)", spec.distance, distance, err)
+		}
+		if delay, err := part2(ws); err != nil || delay != spec.delay {
+			t.Errorf("Expected delay %s, got %s (%v)", spec.delay, delay, err)
+		}
 	}
-}
 
-func TestTrace(t *testing.T) {
-	w := wire{
-		id: 1,
-		path: []instruction{
-			instruction{
-				Direction: "U",
-				Steps:     3,
-			},
-			instruction{
-				Direction: "R",
-				Steps:     5,
-			},
-			instruction{
-				Direction: "D",
-				Steps:     10,
-			},
-			instruction{
-				Direction: "L",
-				Steps:     15,
-			},
-		},
+	ws, err := parse("R2\nU2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part1(ws); err == nil {
+		t.Error("Expected an error for wires that never cross")
 	}
-
-	up, down, left, right := w.bounds()
-
-	cols := abs(left) + abs(right)
-	rows := abs(up) + abs(down)
-
-	startCol := abs(left) - 1
-	startRow := abs(down)
-
-	g := makeGrid(cols, rows)
-
-	trace(g, w, startCol, startRow)
-
-	g[startRow][startCol] = 7
-
-	// for i, _ := range g {
-	// 	fmt.Println(g[len(g)-1-i])
-	// }
-}
-
-func TestEverything(t *testing.T) {
-
-	t.Run("Test1", func(t *testing.T) {
-		path1 := "R75,D30,R83,U83,L12,D49,R71,U7,L72"
-		path2 := "U62,R66,U55,R34,D71,R55,D58,R83"
-
-		dist := getIntersections([]string{path1, path2})
-		if dist != 159 {
-			t.Errorf("Expected 159, got %d", dist)
-		}
-	})
-
-	t.Run("Test2", func(t *testing.T) {
-		path1 := "R98,U47,R26,D63,R33,U87,L62,D20,R33,U53,R51"
-		path2 := "U98,R91,D20,R16,D67,R40,U7,R15,U6,R7"
-
-		dist := getIntersections([]string{path1, path2})
-		if dist != 135 {
-			t.Errorf("Expected 135, got %d", dist)
-		}
-	})
 }
@@ -1,3 +0,0 @@
-module adventofcode/day3
-
-go 1.15
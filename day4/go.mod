@@ -1,3 +0,0 @@
-module adventofcode/day4
-
-go 1.15
@@ -1,33 +1,39 @@
-package main
+package day5
 
 import (
+	"adventofcode/aoc"
 	"adventofcode/intcode"
-	"fmt"
+	"strconv"
+	"strings"
 )
 
-func main() {
-	intcodeProgram := intcode.ReadIntcodeProgram("./input.txt")
+var Solver = aoc.New(parse, part1, part2)
 
-	// Part 1
-	icc := intcode.NewIntCodeComputer(intcode.CopyIntcodeProgram(intcodeProgram))
+func parse(input string) (intcode.IntcodeProgram, error) {
+	return intcode.Load(strings.NewReader(input))
+}
+
+func part1(program intcode.IntcodeProgram) (string, error) {
+	icc := intcode.NewIntCodeComputer(program)
 	go icc.Run()
 	icc.InputChannel <- 1
 	var finalOutput int
 
-run:
 	for {
 		select {
 		case o := <-icc.OutputChannel:
 			finalOutput = o
 		case <-icc.DoneChannel:
-			fmt.Printf("Part 1: %+v\n", finalOutput)
-			break run
+			return strconv.Itoa(finalOutput), nil
 		}
 	}
+}
 
-	// Part 2
-	icc = intcode.NewIntCodeComputer(intcode.CopyIntcodeProgram(intcodeProgram))
+func part2(program intcode.IntcodeProgram) (string, error) {
+	icc := intcode.NewIntCodeComputer(program)
 	go icc.Run()
 	icc.InputChannel <- 5
-	fmt.Printf("Part 2: %+v\n", <-icc.OutputChannel)
+	output := <-icc.OutputChannel
+	<-icc.DoneChannel
+	return strconv.Itoa(output), nil
 }
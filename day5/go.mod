@@ -1,7 +0,0 @@
-module adventofcode/day5
-
-go 1.15
-
-replace adventofcode/intcode => ../intcode
-
-require adventofcode/intcode v0.0.0
@@ -1,3 +0,0 @@
-module adventofcode/day6
-
-go 1.15
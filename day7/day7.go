@@ -1,94 +1,67 @@
-package main
+package day7
 
 import (
+	"adventofcode/aoc"
 	"adventofcode/intcode"
-	"fmt"
+	"strconv"
+	"strings"
 )
 
-func main() {
-	input := intcode.ReadIntcodeProgram("./input.txt")
+var Solver = aoc.New(parse, part1, part2)
 
-	// Part 1
-	func() {
-		A := intcode.NewIntCodeComputer(nil)
-		B := intcode.NewIntCodeComputer(nil)
-		C := intcode.NewIntCodeComputer(nil)
-		D := intcode.NewIntCodeComputer(nil)
-		E := intcode.NewIntCodeComputer(nil)
-
-		amplifiers := []*intcode.IntCodeComputer{A, B, C, D, E}
-
-		// Normal execution phase settings
-		phases := []int{0, 1, 2, 3, 4}
-		finalSignal := make(chan int)
-
-		maxSignal := 0
-		for _, phase := range permutation(phases) {
-			for i, phaseSetting := range phase {
-				amplifiers[i].Program = intcode.CopyIntcodeProgram(input)
+func parse(input string) (intcode.IntcodeProgram, error) {
+	return intcode.Load(strings.NewReader(input))
+}
 
-				A.OutputChannel = B.InputChannel
-				B.OutputChannel = C.InputChannel
-				C.OutputChannel = D.InputChannel
-				D.OutputChannel = E.InputChannel
-				E.OutputChannel = finalSignal
+func part1(input intcode.IntcodeProgram) (string, error) {
+	// Normal execution phase settings
+	maxSignal := 0
+	for _, phases := range permutation([]int{0, 1, 2, 3, 4}) {
+		maxSignal = max(maxSignal, amplify(input, phases, false))
+	}
+	return strconv.Itoa(maxSignal), nil
+}
 
-				go amplifiers[i].Run()
-				amplifiers[i].InputChannel <- phaseSetting
-			}
+func part2(input intcode.IntcodeProgram) (string, error) {
+	// Feedback loop phase settings
+	maxSignal := 0
+	for _, phases := range permutation([]int{5, 6, 7, 8, 9}) {
+		maxSignal = max(maxSignal, amplify(input, phases, true))
+	}
+	return strconv.Itoa(maxSignal), nil
+}
 
-			A.InputChannel <- 0
-			maxSignal = max(maxSignal, <-finalSignal)
+// amplify runs a chain of amplifiers, one per phase setting, and returns the
+// last signal out of the final one. With feedback its signals are fed back
+// into the first amplifier until it halts. No amplifier is left blocked once
+// it returns.
+func amplify(program intcode.IntcodeProgram, phases []int, feedback bool) int {
+	amplifiers := make([]*intcode.IntCodeComputer, len(phases))
+	for i := range amplifiers {
+		amplifiers[i] = intcode.NewIntCodeComputer(intcode.CopyIntcodeProgram(program))
+		amplifiers[i].DoneChannel = make(chan bool, 1)
+		if i > 0 {
+			amplifiers[i].InputChannel = amplifiers[i-1].OutputChannel
 		}
+	}
+	for i, a := range amplifiers {
+		go a.Run()
+		a.InputChannel <- phases[i]
+	}
 
-		fmt.Printf("Part 1: Max Signal = %+v\n", maxSignal)
-	}()
-
-	// Part 2
-	func() {
-		A := intcode.NewIntCodeComputer(nil)
-		B := intcode.NewIntCodeComputer(nil)
-		C := intcode.NewIntCodeComputer(nil)
-		D := intcode.NewIntCodeComputer(nil)
-		E := intcode.NewIntCodeComputer(nil)
-		amplifiers := []*intcode.IntCodeComputer{A, B, C, D, E}
-
-		// Feedback loop phase settings
-		phases := []int{5, 6, 7, 8, 9}
-		finalSignal := make(chan int)
-
-		maxSignal := 0
-		for _, phase := range permutation(phases) {
-			for i, phaseSetting := range phase {
-				amplifiers[i].Program = intcode.CopyIntcodeProgram(input)
-
-				A.OutputChannel = B.InputChannel
-				B.OutputChannel = C.InputChannel
-				C.OutputChannel = D.InputChannel
-				D.OutputChannel = E.InputChannel
-				E.OutputChannel = A.InputChannel
-
-				go amplifiers[i].Run()
-				amplifiers[i].InputChannel <- phaseSetting
-			}
-
-			A.InputChannel <- 0
-
-		run:
-			for {
-				select {
-				case <-A.DoneChannel:
-					E.OutputChannel = finalSignal
-				case signal := <-finalSignal:
-					maxSignal = max(maxSignal, signal)
-				case <-E.DoneChannel:
-					break run
-				}
-			}
+	first, last := amplifiers[0], amplifiers[len(amplifiers)-1]
+	first.InputChannel <- 0
+	for {
+		signal := <-last.OutputChannel
+		if !feedback {
+			return signal
 		}
-
-		fmt.Printf("Part 2: Max Signal = %+v\n", maxSignal)
-	}()
+		select {
+		case first.InputChannel <- signal:
+		case <-last.DoneChannel:
+			return signal
+		}
+	}
 }
 
 func permutation(xs []int) (permuts [][]int) {
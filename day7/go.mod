@@ -1,7 +0,0 @@
-module adventofcode/day7
-
-go 1.15
-
-replace adventofcode/intcode => ../intcode
-
-require adventofcode/intcode v0.0.0
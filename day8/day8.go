@@ -1,114 +1,43 @@
-package main
+package day8
 
 import (
-	"fmt"
-	"io/ioutil"
-	"math"
+	"adventofcode/aoc"
+	"adventofcode/imaging"
+	"adventofcode/ocr"
+	"adventofcode/sif"
+	"image/color"
 	"strconv"
 )
 
 const rows = 6
 const cols = 25
 
-func main() {
-	input, _ := ioutil.ReadFile("./input.txt")
-
-	encImage := getEncodedSpaceImage(string(input), cols, rows)
-
-	// Part 1
-	func() {
-		var layerWithFewest0s int
-		fewest0s := math.MaxInt64
-
-		for i, layer := range encImage {
-			amountOf0s := 0
-			for _, row := range layer {
-				amountOf0s += count(row, 0)
-			}
-			if amountOf0s < fewest0s {
-				layerWithFewest0s = i
-				fewest0s = amountOf0s
-			}
-		}
-
-		var amountOf1s int
-		var amountOf2s int
-		for _, row := range encImage[layerWithFewest0s] {
-			amountOf1s += count(row, 1)
-			amountOf2s += count(row, 2)
-		}
-
-		result := amountOf1s * amountOf2s
-		fmt.Printf("Part 1: %d\n", result)
-	}()
-
-	// Part 2
-
-	func() {
-		var image [rows][cols]int
-
-		for row := 0; row < rows; row++ {
-			for col := 0; col < cols; col++ {
-				for layer := 0; layer < len(encImage); layer++ {
-					value := encImage[layer][row][col]
-					if layer == 0 {
-						image[row][col] = value
-					} else {
-						switch image[row][col] {
-						case 2:
-							image[row][col] = value
-						}
-					}
-				}
-			}
-		}
-
-		fmt.Println("Part 2:")
-		for _, row := range image {
-			for _, col := range row {
-				if col == 1 {
-					fmt.Printf("0")
-				} else {
-					fmt.Printf(" ")
-				}
-			}
-			fmt.Printf("\n")
-		}
-	}()
-
+var pixelColors = map[int]color.Color{
+	sif.Black: color.Black,
+	sif.White: color.White,
 }
 
-func getEncodedSpaceImage(s string, width, height int) [] /*layer*/ [] /*row*/ [] /*col*/ int {
-	layers := len(s) / (width * height)
-	image := make([][][]int, layers)
+var Solver = With(aoc.Output{})
 
-	for layer := 0; layer < layers; layer++ {
-		image[layer] = make([][]int, height)
-		for row := 0; row < height; row++ {
-			image[layer][row] = make([]int, width)
-			for col := 0; col < width; col++ {
-				index := col + row*width + layer*width*height
-				num, _ := strconv.Atoi(string(s[index]))
-				image[layer][row][col] = num
-			}
-		}
-	}
+// With returns a Solver whose Part 2 adds the decoded image to out.Recorder.
+func With(out aoc.Output) aoc.Solver {
+	return aoc.New(parse, part1, func(img *sif.Image) (string, error) {
+		return part2(img, out.Recorder)
+	})
+}
 
-	return image
+func parse(input string) (*sif.Image, error) {
+	return sif.Decode(input, cols, rows)
 }
 
-func count(haystack []int, needle int) (count int) {
-	for _, v := range haystack {
-		if v == needle {
-			count++
-		}
-	}
-	return
+func part1(img *sif.Image) (string, error) {
+	return strconv.Itoa(img.Checksum()), nil
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+func part2(img *sif.Image, recorder *imaging.Recorder) (string, error) {
+	g := img.Flatten()
+	if recorder != nil {
+		recorder.Add(imaging.Image[int](g, g.Bounds(), imaging.Colors(pixelColors, color.Transparent)))
 	}
-	return b
+	return ocr.Read[int](g, sif.White)
 }
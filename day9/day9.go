@@ -1,42 +1,38 @@
-package main
+package day9
 
 import (
+	"adventofcode/aoc"
 	"adventofcode/intcode"
-	"fmt"
+	"strconv"
+	"strings"
 )
 
-func main() {
-	program := intcode.ReadIntcodeProgram("./input.txt")
+var Solver = aoc.New(parse, part1, part2)
 
-	// Part 1
-	func() {
-		icc := intcode.NewIntCodeComputer(program)
-		go icc.Run()
-		icc.InputChannel <- 1
+func parse(input string) (intcode.IntcodeProgram, error) {
+	return intcode.Load(strings.NewReader(input))
+}
 
-		for {
-			select {
-			case o := <-icc.OutputChannel:
-				fmt.Printf("Part 1: %d\n", o)
-			case <-icc.DoneChannel:
-				return
-			}
-		}
-	}()
+func part1(program intcode.IntcodeProgram) (string, error) {
+	return strconv.Itoa(runBOOST(program, 1)), nil
+}
+
+func part2(program intcode.IntcodeProgram) (string, error) {
+	return strconv.Itoa(runBOOST(program, 2)), nil
+}
 
-	// Part 2
-	func() {
-		icc := intcode.NewIntCodeComputer(program)
-		go icc.Run()
-		icc.InputChannel <- 2
+func runBOOST(program intcode.IntcodeProgram, mode int) int {
+	icc := intcode.NewIntCodeComputer(program)
+	go icc.Run()
+	icc.InputChannel <- mode
 
-		for {
-			select {
-			case o := <-icc.OutputChannel:
-				fmt.Printf("Part 2: %d\n", o)
-			case <-icc.DoneChannel:
-				return
-			}
+	var keycode int
+	for {
+		select {
+		case o := <-icc.OutputChannel:
+			keycode = o
+		case <-icc.DoneChannel:
+			return keycode
 		}
-	}()
+	}
 }
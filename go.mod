@@ -0,0 +1,3 @@
+module adventofcode
+
+go 1.18
@@ -0,0 +1,133 @@
+package grid
+
+// Dense is a fixed size grid with its top left cell at (0, 0).
+type Dense[T any] struct {
+	Rows  int
+	Cols  int
+	Cells []T
+}
+
+func NewDense[T any](rows, cols int, fill T) *Dense[T] {
+	d := &Dense[T]{Rows: rows, Cols: cols, Cells: make([]T, rows*cols)}
+	for i := range d.Cells {
+		d.Cells[i] = fill
+	}
+	return d
+}
+
+// FromRows builds a dense grid from equal length rows, such as the lines of
+// a puzzle input. Short rows are padded with the zero value.
+func FromRows[T any](rows [][]T) *Dense[T] {
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	var zero T
+	d := NewDense(len(rows), cols, zero)
+	for r, row := range rows {
+		copy(d.Cells[r*cols:], row)
+	}
+	return d
+}
+
+func (d *Dense[T]) In(p Point) bool {
+	return p.Row >= 0 && p.Row < d.Rows && p.Col >= 0 && p.Col < d.Cols
+}
+
+func (d *Dense[T]) At(p Point) (T, bool) {
+	if !d.In(p) {
+		var zero T
+		return zero, false
+	}
+	return d.Cells[p.Row*d.Cols+p.Col], true
+}
+
+// Get returns the value at p, or the zero value outside the grid.
+func (d *Dense[T]) Get(p Point) T {
+	v, _ := d.At(p)
+	return v
+}
+
+// Set sets the value at p. Points outside the grid are ignored.
+func (d *Dense[T]) Set(p Point, v T) {
+	if d.In(p) {
+		d.Cells[p.Row*d.Cols+p.Col] = v
+	}
+}
+
+func (d *Dense[T]) Bounds() Rect {
+	return Rect{Max: Point{d.Rows - 1, d.Cols - 1}}
+}
+
+// Row returns row r, sharing storage with the grid.
+func (d *Dense[T]) Row(r int) []T {
+	return d.Cells[r*d.Cols : (r+1)*d.Cols]
+}
+
+// Points returns every point of the grid in row major order.
+func (d *Dense[T]) Points() []Point {
+	points := make([]Point, 0, len(d.Cells))
+	for row := 0; row < d.Rows; row++ {
+		for col := 0; col < d.Cols; col++ {
+			points = append(points, Point{row, col})
+		}
+	}
+	return points
+}
+
+// Map builds a new grid by applying f to every cell.
+func Map[T, U any](d *Dense[T], f func(p Point, v T) U) *Dense[U] {
+	m := &Dense[U]{Rows: d.Rows, Cols: d.Cols, Cells: make([]U, len(d.Cells))}
+	for i, v := range d.Cells {
+		m.Cells[i] = f(Point{i / d.Cols, i % d.Cols}, v)
+	}
+	return m
+}
+
+// transform builds a rows x cols grid whose cell p is d's cell src(p).
+func (d *Dense[T]) transform(rows, cols int, src func(p Point) Point) *Dense[T] {
+	t := &Dense[T]{Rows: rows, Cols: cols, Cells: make([]T, rows*cols)}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			t.Cells[row*cols+col] = d.Get(src(Point{row, col}))
+		}
+	}
+	return t
+}
+
+// RotateRight returns d rotated a quarter turn clockwise.
+func (d *Dense[T]) RotateRight() *Dense[T] {
+	return d.transform(d.Cols, d.Rows, func(p Point) Point {
+		return Point{d.Rows - 1 - p.Col, p.Row}
+	})
+}
+
+// RotateLeft returns d rotated a quarter turn counterclockwise.
+func (d *Dense[T]) RotateLeft() *Dense[T] {
+	return d.transform(d.Cols, d.Rows, func(p Point) Point {
+		return Point{p.Col, d.Cols - 1 - p.Row}
+	})
+}
+
+// FlipHorizontal mirrors d left to right.
+func (d *Dense[T]) FlipHorizontal() *Dense[T] {
+	return d.transform(d.Rows, d.Cols, func(p Point) Point {
+		return Point{p.Row, d.Cols - 1 - p.Col}
+	})
+}
+
+// FlipVertical mirrors d top to bottom.
+func (d *Dense[T]) FlipVertical() *Dense[T] {
+	return d.transform(d.Rows, d.Cols, func(p Point) Point {
+		return Point{d.Rows - 1 - p.Row, p.Col}
+	})
+}
+
+// Crop returns the part of d inside r, which is clipped to the grid.
+func (d *Dense[T]) Crop(r Rect) *Dense[T] {
+	r.Min.Row, r.Min.Col = max(r.Min.Row, 0), max(r.Min.Col, 0)
+	r.Max.Row, r.Max.Col = min(r.Max.Row, d.Rows-1), min(r.Max.Col, d.Cols-1)
+	return d.transform(r.Rows(), r.Cols(), func(p Point) Point {
+		return p.Add(r.Min)
+	})
+}
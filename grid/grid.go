@@ -0,0 +1,165 @@
+// Package grid provides points, headings, bounds and sparse and dense 2D
+// grids, along with a renderer for drawing them in a terminal.
+package grid
+
+import "fmt"
+
+// Point is a cell position. Rows grow downward, so Up is Row-1.
+type Point struct {
+	Row int
+	Col int
+}
+
+func (p Point) Add(o Point) Point {
+	return Point{Row: p.Row + o.Row, Col: p.Col + o.Col}
+}
+
+// Move returns the point one step away in direction d.
+func (p Point) Move(d Direction) Point {
+	return p.Add(d.Delta())
+}
+
+// Neighbors4 returns the orthogonal neighbors in clockwise order from Up.
+func (p Point) Neighbors4() []Point {
+	return []Point{p.Move(Up), p.Move(Right), p.Move(Down), p.Move(Left)}
+}
+
+// Neighbors8 returns the orthogonal and diagonal neighbors in clockwise
+// order from Up.
+func (p Point) Neighbors8() []Point {
+	return []Point{
+		{p.Row - 1, p.Col}, {p.Row - 1, p.Col + 1}, {p.Row, p.Col + 1}, {p.Row + 1, p.Col + 1},
+		{p.Row + 1, p.Col}, {p.Row + 1, p.Col - 1}, {p.Row, p.Col - 1}, {p.Row - 1, p.Col - 1},
+	}
+}
+
+// Manhattan returns the taxicab distance between p and o.
+func (p Point) Manhattan(o Point) int {
+	return abs(p.Row-o.Row) + abs(p.Col-o.Col)
+}
+
+// Direction is a heading on the grid, in clockwise order.
+type Direction int
+
+const (
+	Up Direction = iota
+	Right
+	Down
+	Left
+)
+
+var directionNames = [...]string{"U", "R", "D", "L"}
+
+// ParseDirection parses the single letter headings used by puzzle input:
+// U, R, D, L (or N, E, S, W).
+func ParseDirection(s string) (Direction, error) {
+	switch s {
+	case "U", "N", "^":
+		return Up, nil
+	case "R", "E", ">":
+		return Right, nil
+	case "D", "S", "v":
+		return Down, nil
+	case "L", "W", "<":
+		return Left, nil
+	}
+	return Up, fmt.Errorf("grid: unknown direction %q", s)
+}
+
+func (d Direction) String() string {
+	return directionNames[d]
+}
+
+func (d Direction) TurnLeft() Direction {
+	return (d + 3) % 4
+}
+
+func (d Direction) TurnRight() Direction {
+	return (d + 1) % 4
+}
+
+func (d Direction) Reverse() Direction {
+	return (d + 2) % 4
+}
+
+// Delta is the change in position of one step in direction d.
+func (d Direction) Delta() Point {
+	switch d {
+	case Up:
+		return Point{Row: -1}
+	case Right:
+		return Point{Col: 1}
+	case Down:
+		return Point{Row: 1}
+	default:
+		return Point{Col: -1}
+	}
+}
+
+// Rect is an inclusive rectangle of cells.
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+// Bounds returns the smallest rectangle holding every point. It is empty
+// when there are no points.
+func Bounds(points []Point) Rect {
+	if len(points) == 0 {
+		return Rect{Max: Point{-1, -1}}
+	}
+	r := Rect{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		r = r.Extend(p)
+	}
+	return r
+}
+
+// Extend grows r to hold p.
+func (r Rect) Extend(p Point) Rect {
+	if r.Empty() {
+		return Rect{Min: p, Max: p}
+	}
+	r.Min.Row = min(r.Min.Row, p.Row)
+	r.Min.Col = min(r.Min.Col, p.Col)
+	r.Max.Row = max(r.Max.Row, p.Row)
+	r.Max.Col = max(r.Max.Col, p.Col)
+	return r
+}
+
+func (r Rect) Empty() bool {
+	return r.Max.Row < r.Min.Row || r.Max.Col < r.Min.Col
+}
+
+func (r Rect) Rows() int {
+	return max(r.Max.Row-r.Min.Row+1, 0)
+}
+
+func (r Rect) Cols() int {
+	return max(r.Max.Col-r.Min.Col+1, 0)
+}
+
+func (r Rect) Contains(p Point) bool {
+	return p.Row >= r.Min.Row && p.Row <= r.Max.Row && p.Col >= r.Min.Col && p.Col <= r.Max.Col
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,87 @@
+package grid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDirection(t *testing.T) {
+	d := Up
+	for _, expected := range []Direction{Right, Down, Left, Up} {
+		d = d.TurnRight()
+		if d != expected {
+			t.Errorf("Expected %s, got %s", expected, d)
+		}
+	}
+	if Up.TurnLeft() != Left || Left.Reverse() != Right {
+		t.Error("Expected turning left from U to be L and reversing L to be R")
+	}
+
+	p := Point{}.Move(Up).Move(Up).Move(Right)
+	if p != (Point{Row: -2, Col: 1}) {
+		t.Errorf("Expected (-2, 1), got %v", p)
+	}
+}
+
+func TestSparse(t *testing.T) {
+	g := make(Sparse[int])
+	g.Set(Point{-1, 2}, 1)
+	g.Set(Point{3, -4}, 2)
+
+	bounds := g.Bounds()
+	if bounds != (Rect{Min: Point{-1, -4}, Max: Point{3, 2}}) || bounds.Rows() != 5 || bounds.Cols() != 7 {
+		t.Errorf("Unexpected bounds %+v", bounds)
+	}
+
+	if n := Count[int](g, func(v int) bool { return v > 0 }); n != 2 {
+		t.Errorf("Expected 2 set cells, got %d", n)
+	}
+
+	d := g.Dense(0)
+	if d.Get(Point{0, 6}) != 1 || d.Get(Point{4, 0}) != 2 {
+		t.Errorf("Expected the dense copy to keep values, got %v", d.Cells)
+	}
+
+	if empty := make(Sparse[int]).Bounds(); !empty.Empty() || empty.Rows() != 0 {
+		t.Errorf("Expected empty bounds, got %+v", empty)
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	d := FromRows([][]rune{
+		[]rune("ab"),
+		[]rune("cd"),
+		[]rune("ef"),
+	})
+
+	specs := map[string]struct {
+		Actual   *Dense[rune]
+		Expected string
+	}{
+		"RotateRight":    {d.RotateRight(), "eca\nfdb\n"},
+		"RotateLeft":     {d.RotateLeft(), "bdf\nace\n"},
+		"FlipHorizontal": {d.FlipHorizontal(), "ba\ndc\nfe\n"},
+		"FlipVertical":   {d.FlipVertical(), "ef\ncd\nab\n"},
+		"Crop":           {d.Crop(Rect{Min: Point{1, 1}, Max: Point{5, 5}}), "d\nf\n"},
+	}
+
+	for name, spec := range specs {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			Render[rune](&b, spec.Actual, func(p Point, v rune, ok bool) string { return string(v) })
+			if b.String() != spec.Expected {
+				t.Errorf("Expected %q, got %q", spec.Expected, b.String())
+			}
+		})
+	}
+}
+
+func TestRenderSymbols(t *testing.T) {
+	g := Sparse[int]{{0, 0}: 1, {1, 2}: 2, {0, 2}: 0}
+
+	var b bytes.Buffer
+	Render[int](&b, g, Symbols(map[int]string{1: "#", 2: "🟩"}, "."))
+	if expected := "#..\n..🟩\n"; b.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, b.String())
+	}
+}
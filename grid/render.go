@@ -0,0 +1,39 @@
+package grid
+
+import (
+	"bufio"
+	"io"
+)
+
+// Renderer draws a single cell. ok is false for cells that are not set.
+type Renderer[T any] func(p Point, v T, ok bool) string
+
+// Symbols renders cells by looking their value up in symbols. Unset cells
+// and values missing from symbols render as blank.
+func Symbols[T comparable](symbols map[T]string, blank string) Renderer[T] {
+	return func(p Point, v T, ok bool) string {
+		if s, found := symbols[v]; ok && found {
+			return s
+		}
+		return blank
+	}
+}
+
+// Render writes every row of g inside its bounds, one line per row.
+func Render[T any](w io.Writer, g Grid[T], r Renderer[T]) error {
+	return RenderRect(w, g, g.Bounds(), r)
+}
+
+// RenderRect writes the cells of g inside bounds, one line per row.
+func RenderRect[T any](w io.Writer, g Grid[T], bounds Rect, r Renderer[T]) error {
+	bw := bufio.NewWriter(w)
+	for row := bounds.Min.Row; row <= bounds.Max.Row; row++ {
+		for col := bounds.Min.Col; col <= bounds.Max.Col; col++ {
+			p := Point{row, col}
+			v, ok := g.At(p)
+			bw.WriteString(r(p, v, ok))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
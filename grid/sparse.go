@@ -0,0 +1,70 @@
+package grid
+
+// Grid is implemented by both Sparse and Dense grids.
+type Grid[T any] interface {
+	Bounds() Rect
+	At(p Point) (T, bool)
+}
+
+// Sparse is a grid holding only the cells that have been set, for grids
+// that grow in every direction as they are explored.
+type Sparse[T any] map[Point]T
+
+func (g Sparse[T]) At(p Point) (T, bool) {
+	v, ok := g[p]
+	return v, ok
+}
+
+// Get returns the value at p, or the zero value if it has not been set.
+func (g Sparse[T]) Get(p Point) T {
+	return g[p]
+}
+
+func (g Sparse[T]) Set(p Point, v T) {
+	g[p] = v
+}
+
+// Bounds returns the smallest rectangle holding every set cell.
+func (g Sparse[T]) Bounds() Rect {
+	r := Rect{Max: Point{-1, -1}}
+	for p := range g {
+		r = r.Extend(p)
+	}
+	return r
+}
+
+// Crop returns the cells of g inside r.
+func (g Sparse[T]) Crop(r Rect) Sparse[T] {
+	c := make(Sparse[T])
+	for p, v := range g {
+		if r.Contains(p) {
+			c[p] = v
+		}
+	}
+	return c
+}
+
+// Dense copies g into a dense grid covering its bounds. Unset cells hold
+// fill, and the dense grid's origin is the top left of the bounds.
+func (g Sparse[T]) Dense(fill T) *Dense[T] {
+	bounds := g.Bounds()
+	d := NewDense(bounds.Rows(), bounds.Cols(), fill)
+	for p, v := range g {
+		d.Set(Point{p.Row - bounds.Min.Row, p.Col - bounds.Min.Col}, v)
+	}
+	return d
+}
+
+// Count returns the number of cells for which f is true.
+func Count[T any](g Grid[T], f func(v T) bool) int {
+	n := 0
+	bounds := g.Bounds()
+	for row := bounds.Min.Row; row <= bounds.Max.Row; row++ {
+		for col := bounds.Min.Col; col <= bounds.Max.Col; col++ {
+			if v, ok := g.At(Point{row, col}); ok && f(v) {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// WriteGIF writes frames as an animated GIF, each shown for delay and scaled
+// up by scale. Frames are drawn at the top left of a canvas big enough for
+// the largest. Frames with at most 256 colors between them keep their exact
+// colors; otherwise they are dithered to a standard palette.
+func WriteGIF(w io.Writer, frames []image.Image, delay time.Duration, scale int) error {
+	if scale < 1 {
+		scale = 1
+	}
+
+	p := framePalette(frames)
+	anim := &gif.GIF{}
+	for _, frame := range frames {
+		scaled := Scale(frame, scale)
+		b := scaled.Bounds()
+		paletted := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), p)
+		if len(p) == len(palette.Plan9) {
+			draw.FloydSteinberg.Draw(paletted, paletted.Rect, scaled, b.Min)
+		} else {
+			draw.Draw(paletted, paletted.Rect, scaled, b.Min, draw.Src)
+		}
+
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+		if b.Dx() > anim.Config.Width {
+			anim.Config.Width = b.Dx()
+		}
+		if b.Dy() > anim.Config.Height {
+			anim.Config.Height = b.Dy()
+		}
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// framePalette collects the colors used by frames, falling back to the Plan 9
+// palette when there are too many.
+func framePalette(frames []image.Image) color.Palette {
+	seen := make(map[color.RGBA]bool)
+	var p color.Palette
+	for _, frame := range frames {
+		b := frame.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := color.RGBAModel.Convert(frame.At(x, y)).(color.RGBA)
+				if seen[c] {
+					continue
+				}
+				if len(p) == 256 {
+					return palette.Plan9
+				}
+				seen[c] = true
+				p = append(p, c)
+			}
+		}
+	}
+	if len(p) == 0 {
+		p = append(p, color.Black)
+	}
+	return p
+}
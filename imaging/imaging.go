@@ -0,0 +1,60 @@
+// Package imaging saves grids as PNG, SVG or animated GIF images using only
+// the standard library. Grids are first drawn one pixel per cell, then
+// scaled up when written.
+package imaging
+
+import (
+	"adventofcode/grid"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Colorer picks the color of a single cell. ok is false for cells that are
+// not set.
+type Colorer[T any] func(p grid.Point, v T, ok bool) color.Color
+
+// Colors colors cells by looking their value up in palette. Unset cells and
+// values missing from palette are blank.
+func Colors[T comparable](palette map[T]color.Color, blank color.Color) Colorer[T] {
+	return func(p grid.Point, v T, ok bool) color.Color {
+		if c, found := palette[v]; ok && found {
+			return c
+		}
+		return blank
+	}
+}
+
+// Image draws the cells of g inside bounds, one pixel per cell.
+func Image[T any](g grid.Grid[T], bounds grid.Rect, c Colorer[T]) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Cols(), bounds.Rows()))
+	for row := bounds.Min.Row; row <= bounds.Max.Row; row++ {
+		for col := bounds.Min.Col; col <= bounds.Max.Col; col++ {
+			p := grid.Point{Row: row, Col: col}
+			v, ok := g.At(p)
+			img.Set(col-bounds.Min.Col, row-bounds.Min.Row, c(p, v, ok))
+		}
+	}
+	return img
+}
+
+// Scale enlarges img so every pixel becomes a scale by scale square.
+func Scale(img image.Image, scale int) image.Image {
+	if scale <= 1 {
+		return img
+	}
+	b := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, b.Dx()*scale, b.Dy()*scale))
+	for y := 0; y < scaled.Rect.Dy(); y++ {
+		for x := 0; x < scaled.Rect.Dx(); x++ {
+			scaled.Set(x, y, img.At(b.Min.X+x/scale, b.Min.Y+y/scale))
+		}
+	}
+	return scaled
+}
+
+// WritePNG writes img scaled up by scale.
+func WritePNG(w io.Writer, img image.Image, scale int) error {
+	return png.Encode(w, Scale(img, scale))
+}
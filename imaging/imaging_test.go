@@ -0,0 +1,126 @@
+package imaging
+
+import (
+	"adventofcode/grid"
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	green = color.RGBA{0, 255, 0, 255}
+)
+
+func sample() *image.RGBA {
+	g := grid.Sparse[int]{{Row: 0, Col: 0}: 1, {Row: 0, Col: 1}: 1, {Row: 1, Col: 2}: 2}
+	return Image[int](g, g.Bounds(), Colors(map[int]color.Color{1: red, 2: green}, color.Transparent))
+}
+
+func TestImage(t *testing.T) {
+	img := sample()
+	if img.Bounds() != image.Rect(0, 0, 3, 2) {
+		t.Fatalf("Expected 3x2 image, got %v", img.Bounds())
+	}
+
+	specs := []struct {
+		x, y     int
+		expected color.Color
+	}{
+		{0, 0, red},
+		{1, 0, red},
+		{2, 0, color.RGBA{}},
+		{2, 1, green},
+	}
+	for _, spec := range specs {
+		if c := img.At(spec.x, spec.y); c != spec.expected {
+			t.Errorf("(%d,%d): expected %v, got %v", spec.x, spec.y, spec.expected, c)
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var b bytes.Buffer
+	if err := WritePNG(&b, sample(), 4); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 12 || img.Bounds().Dy() != 8 {
+		t.Fatalf("Expected 12x8 image, got %v", img.Bounds())
+	}
+	if r, g, _, _ := img.At(11, 7).RGBA(); r != 0 || g != 0xffff {
+		t.Errorf("Expected the bottom right to be green, got %v", img.At(11, 7))
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteSVG(&b, sample(), 10); err != nil {
+		t.Fatal(err)
+	}
+	svg := b.String()
+	for _, expected := range []string{
+		`width="30" height="20"`,
+		`<rect x="0" y="0" width="20" height="10" fill="#ff0000"/>`,
+		`<rect x="20" y="10" width="10" height="10" fill="#00ff00"/>`,
+	} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("Expected %q in\n%s", expected, svg)
+		}
+	}
+	if n := strings.Count(svg, "<rect"); n != 2 {
+		t.Errorf("Expected 2 rects, got %d", n)
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	small := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	var b bytes.Buffer
+	if err := WriteGIF(&b, []image.Image{small, sample()}, 50*time.Millisecond, 2); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 || anim.Delay[1] != 5 {
+		t.Fatalf("Expected 2 frames of 5, got %d frames with delays %v", len(anim.Image), anim.Delay)
+	}
+	if anim.Config.Width != 6 || anim.Config.Height != 4 {
+		t.Errorf("Expected a 6x4 canvas, got %dx%d", anim.Config.Width, anim.Config.Height)
+	}
+	if c := anim.Image[1].At(5, 3); color.RGBAModel.Convert(c) != green {
+		t.Errorf("Expected exact green, got %v", c)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	r := NewRecorder(2, 100*time.Millisecond)
+	if err := r.Save(filepath.Join(dir, "empty.png")); err == nil {
+		t.Error("Expected an error saving an empty recording")
+	}
+
+	r.Add(sample())
+	for _, name := range []string{"out.png", "out.svg", "out.gif"} {
+		if err := r.Save(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Errorf("%s: expected a file to be written", name)
+		}
+	}
+	if err := r.Save(filepath.Join(dir, "out.bmp")); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,66 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Recorder collects the images a puzzle draws so they can be saved once it
+// is solved.
+type Recorder struct {
+	Scale  int
+	Delay  time.Duration
+	frames []image.Image
+}
+
+// NewRecorder returns a recorder that scales images up by scale and shows
+// each GIF frame for delay.
+func NewRecorder(scale int, delay time.Duration) *Recorder {
+	return &Recorder{Scale: scale, Delay: delay}
+}
+
+// Add records the next frame.
+func (r *Recorder) Add(img image.Image) {
+	r.frames = append(r.frames, img)
+}
+
+// Frames returns every recorded frame.
+func (r *Recorder) Frames() []image.Image {
+	return r.frames
+}
+
+// Save writes the recording to filename, picking the format from its
+// extension: .gif writes every frame, .png and .svg only the last.
+func (r *Recorder) Save(filename string) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("imaging: nothing was drawn to save to %s", filename)
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".png" && ext != ".svg" && ext != ".gif" {
+		return fmt.Errorf("imaging: unknown image format %q", ext)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	last := r.frames[len(r.frames)-1]
+	switch ext {
+	case ".png":
+		err = WritePNG(f, last, r.Scale)
+	case ".svg":
+		err = WriteSVG(f, last, r.Scale)
+	case ".gif":
+		err = WriteGIF(f, r.frames, r.Delay, r.Scale)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
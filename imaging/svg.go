@@ -0,0 +1,44 @@
+package imaging
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// WriteSVG writes img as an SVG of scale by scale squares. Runs of pixels of
+// the same color in a row become a single rectangle; transparent pixels are
+// left out.
+func WriteSVG(w io.Writer, img image.Image, scale int) error {
+	if scale < 1 {
+		scale = 1
+	}
+	b := img.Bounds()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		b.Dx()*scale, b.Dy()*scale, b.Dx()*scale, b.Dy()*scale)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			end := x + 1
+			for end < b.Max.X && color.NRGBAModel.Convert(img.At(end, y)) == c {
+				end++
+			}
+			if c.A > 0 {
+				fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="#%02x%02x%02x"`,
+					(x-b.Min.X)*scale, (y-b.Min.Y)*scale, (end-x)*scale, scale, c.R, c.G, c.B)
+				if c.A < 255 {
+					fmt.Fprintf(bw, ` fill-opacity="%.3f"`, float64(c.A)/255)
+				}
+				fmt.Fprint(bw, "/>\n")
+			}
+			x = end
+		}
+	}
+
+	fmt.Fprint(bw, "</svg>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,242 @@
+package intcode
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var mnemonics = map[int]string{
+	add:         "add",
+	mult:        "mul",
+	input:       "in",
+	output:      "out",
+	jumpIfTrue:  "jnz",
+	jumpIfFalse: "jz",
+	lessThan:    "lt",
+	equals:      "eq",
+	setRelBase:  "arb",
+	halt:        "hlt",
+}
+
+var opcodes = map[string]int{}
+
+// writes is which operand each instruction that writes memory writes to. It
+// must be a position or relative operand.
+var writes = map[int]int{
+	add:      2,
+	mult:     2,
+	input:    0,
+	lessThan: 2,
+	equals:   2,
+}
+
+func init() {
+	for opcode, mnemonic := range mnemonics {
+		opcodes[mnemonic] = opcode
+	}
+}
+
+// Instruction is a single decoded instruction, or a data word when it could
+// not be decoded as one.
+type Instruction struct {
+	Addr   int
+	Opcode int
+	Params []Param
+	Data   bool
+	Words  []int
+}
+
+func (in Instruction) String() string {
+	if in.Data {
+		return "data " + joinInts(in.Words)
+	}
+
+	operands := make([]string, len(in.Params))
+	for i, p := range in.Params {
+		switch p.Mode {
+		case parameter:
+			operands[i] = fmt.Sprintf("[%d]", p.ValueOrOffset)
+		case immediate:
+			operands[i] = strconv.Itoa(p.ValueOrOffset)
+		case relative:
+			operands[i] = fmt.Sprintf("[rb%+d]", p.ValueOrOffset)
+		}
+	}
+
+	if len(operands) == 0 {
+		return mnemonics[in.Opcode]
+	}
+	return mnemonics[in.Opcode] + " " + strings.Join(operands, ", ")
+}
+
+// Decode decodes the instruction at addr. Words that are not a valid
+// instruction (unknown opcode, bad or surplus modes, an immediate operand
+// written to, or running off the end of the program) decode as a single data
+// word.
+func Decode(program []int, addr int) Instruction {
+	value := program[addr]
+	data := Instruction{Addr: addr, Data: true, Words: []int{value}}
+
+	if value < 0 {
+		return data
+	}
+
+	opcode := value % 100
+	arity, ok := opcodeArity[opcode]
+	if !ok || addr+arity >= len(program) {
+		return data
+	}
+
+	params := make([]Param, arity)
+	modes := value / 100
+	for i := range params {
+		params[i].Mode = modes % 10
+		params[i].ValueOrOffset = program[addr+i+1]
+		if params[i].Mode > relative {
+			return data
+		}
+		modes /= 10
+	}
+	if modes != 0 {
+		return data
+	}
+	if w, ok := writes[opcode]; ok && params[w].Mode == immediate {
+		return data
+	}
+
+	words := make([]int, arity+1)
+	copy(words, program[addr:])
+	return Instruction{Addr: addr, Opcode: opcode, Params: params, Words: words}
+}
+
+// Disassemble decodes the whole program with a linear sweep.
+func Disassemble(program []int) []Instruction {
+	var instructions []Instruction
+	for addr := 0; addr < len(program); {
+		in := Decode(program, addr)
+		instructions = append(instructions, in)
+		addr += len(in.Words)
+	}
+	return instructions
+}
+
+// WriteListing writes the disassembler's listing format, one "addr: instruction"
+// per line. A listing can be loaded back with Load.
+func WriteListing(w io.Writer, program []int) error {
+	for _, in := range Disassemble(program) {
+		if _, err := fmt.Fprintf(w, "%04d: %s\n", in.Addr, in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Assemble parses the assembler text format. Each line holds one mnemonic
+// followed by comma separated operands: 5 (immediate), [5] (position) or
+// [rb+5] (relative). "data" emits its operands as raw words. Lines may be
+// prefixed with their "addr:" as written by WriteListing, in which case the
+// address is checked.
+func Assemble(src string) (IntcodeProgram, error) {
+	program := IntcodeProgram{}
+
+	for i, line := range strings.Split(src, "\n") {
+		tokens := tokenize(line)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		if strings.HasSuffix(tokens[0].text, ":") {
+			addr, err := strconv.Atoi(strings.TrimSuffix(tokens[0].text, ":"))
+			if err != nil {
+				return nil, tokens[0].errorf(i+1, "invalid address")
+			}
+			if addr != len(program) {
+				return nil, tokens[0].errorf(i+1, "address does not match position %d", len(program))
+			}
+			tokens = tokens[1:]
+			if len(tokens) == 0 {
+				continue
+			}
+		}
+
+		mnemonic, operands := tokens[0], tokens[1:]
+
+		if mnemonic.text == "data" {
+			for _, t := range operands {
+				v, err := strconv.Atoi(t.text)
+				if err != nil {
+					return nil, t.errorf(i+1, "invalid data word")
+				}
+				program = append(program, v)
+			}
+			continue
+		}
+
+		opcode, ok := opcodes[mnemonic.text]
+		if !ok {
+			return nil, mnemonic.errorf(i+1, "unknown mnemonic")
+		}
+		if len(operands) != opcodeArity[opcode] {
+			return nil, mnemonic.errorf(i+1, "expects %d operands, got %d", opcodeArity[opcode], len(operands))
+		}
+
+		params := make([]Param, len(operands))
+		for j, t := range operands {
+			p, err := parseOperand(t.text)
+			if err != nil {
+				return nil, t.errorf(i+1, "invalid operand")
+			}
+			if w, ok := writes[opcode]; ok && w == j && p.Mode == immediate {
+				return nil, t.errorf(i+1, "cannot write to an immediate operand")
+			}
+			params[j] = p
+		}
+
+		program = append(program, encode(opcode, params))
+		for _, p := range params {
+			program = append(program, p.ValueOrOffset)
+		}
+	}
+
+	return program, nil
+}
+
+func parseOperand(s string) (Param, error) {
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		v, err := strconv.Atoi(s)
+		return Param{Mode: immediate, ValueOrOffset: v}, err
+	}
+
+	s = s[1 : len(s)-1]
+	if strings.HasPrefix(s, "rb") {
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "rb"), "+")
+		if s == "" {
+			return Param{Mode: relative}, nil
+		}
+		v, err := strconv.Atoi(s)
+		return Param{Mode: relative, ValueOrOffset: v}, err
+	}
+
+	v, err := strconv.Atoi(s)
+	return Param{Mode: parameter, ValueOrOffset: v}, err
+}
+
+func encode(opcode int, params []Param) int {
+	value := opcode
+	scale := 100
+	for _, p := range params {
+		value += p.Mode * scale
+		scale *= 10
+	}
+	return value
+}
+
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ", ")
+}
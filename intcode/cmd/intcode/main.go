@@ -0,0 +1,42 @@
+// Command intcode is a toolbox for poking at Intcode programs.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: intcode <command> [arguments]
+
+commands:
+  repl [program]    interactively load, patch, run and inspect a program
+  memdiff [flags] <program a> [program b]
+                    run two programs to completion and compare their memory
+  web [flags] <program>
+                    run a program with a live view at http://localhost:8019/
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "repl":
+		err = repl(os.Args[2:], os.Stdin, os.Stdout)
+	case "memdiff":
+		err = memdiffCmd(os.Args[2:], os.Stdout)
+	case "web":
+		err = webCmd(os.Args[2:], os.Stdin, os.Stdout)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "intcode %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"adventofcode/intcode"
+	"adventofcode/intcode/memdiff"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// side is one of the two runs being compared.
+type side struct {
+	program string
+	patches string
+	inputs  string
+}
+
+func memdiffCmd(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("memdiff", flag.ContinueOnError)
+	var a, b side
+	fs.StringVar(&a.patches, "a-patch", "", "patches for run a, e.g. 1=12,2=2")
+	fs.StringVar(&b.patches, "b-patch", "", "patches for run b")
+	fs.StringVar(&a.inputs, "a-input", "", "comma separated inputs for run a")
+	fs.StringVar(&b.inputs, "b-input", "", "comma separated inputs for run b")
+	max := fs.Int("max", 10000000, "maximum steps per run")
+	stop := fs.Bool("stop-at-input", false, "end a run that needs more input than it was given instead of failing")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: intcode memdiff [flags] <program a> [program b]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch fs.NArg() {
+	case 1:
+		a.program, b.program = fs.Arg(0), fs.Arg(0)
+	case 2:
+		a.program, b.program = fs.Arg(0), fs.Arg(1)
+	default:
+		fs.Usage()
+		return errors.New("expected one or two programs")
+	}
+
+	iccA, trackerA, err := a.run(*max, *stop)
+	if err != nil {
+		return fmt.Errorf("run a: %w", err)
+	}
+	iccB, trackerB, err := b.run(*max, *stop)
+	if err != nil {
+		return fmt.Errorf("run b: %w", err)
+	}
+
+	fmt.Fprintf(out, "a: %s, b: %s\n", ended(iccA), ended(iccB))
+	regions := memdiff.Diff(iccA.Program, iccB.Program, trackerA, trackerB)
+	if len(regions) == 0 {
+		fmt.Fprintln(out, "memory is identical")
+		return nil
+	}
+	return memdiff.Print(out, regions)
+}
+
+// run executes the side's program until it halts, feeding it its inputs. If
+// stop is set, running out of input ends the run rather than failing it.
+func (s side) run(max int, stop bool) (*intcode.IntCodeComputer, *memdiff.Tracker, error) {
+	program, err := intcode.LoadFile(s.program)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, patch := range splitList(s.patches) {
+		parts := strings.SplitN(patch, "=", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("invalid patch %q", patch)
+		}
+		addr, err := strconv.Atoi(parts[0])
+		if err != nil || addr < 0 || addr >= len(program) {
+			return nil, nil, fmt.Errorf("invalid patch address %q", parts[0])
+		}
+		value, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid patch value %q", parts[1])
+		}
+		program[addr] = value
+	}
+
+	var inputs []int
+	for _, s := range splitList(s.inputs) {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid input %q", s)
+		}
+		inputs = append(inputs, v)
+	}
+
+	icc := intcode.NewIntCodeComputer(program)
+	icc.InputChannel = make(chan int, len(inputs))
+	icc.OutputChannel = make(chan int, 1)
+	icc.DoneChannel = make(chan bool, 1)
+	tracker := memdiff.Track(icc)
+
+	for _, v := range inputs {
+		icc.InputChannel <- v
+	}
+
+	for icc.Steps < max {
+		if icc.NeedsInput() && len(icc.InputChannel) == 0 {
+			if stop {
+				return icc, tracker, nil
+			}
+			return nil, nil, fmt.Errorf("out of input at %04d after %d steps", icc.IP, icc.Steps)
+		}
+		if !icc.Step() {
+			return icc, tracker, nil
+		}
+		select {
+		case <-icc.OutputChannel:
+		default:
+		}
+	}
+	return nil, nil, fmt.Errorf("did not halt within %d steps", max)
+}
+
+// ended describes where a run ended.
+func ended(icc *intcode.IntCodeComputer) string {
+	if icc.Halted {
+		return fmt.Sprintf("%d steps", icc.Steps)
+	}
+	return fmt.Sprintf("%d steps, out of input at %04d", icc.Steps, icc.IP)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemdiffStopAtInput(t *testing.T) {
+	// in [7]; add 1, 1, [8]; hlt
+	filename := filepath.Join(t.TempDir(), "program.txt")
+	if err := os.WriteFile(filename, []byte("3,7,1101,1,1,8,99,0,0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := memdiffCmd([]string{"-b-input", "5", filename}, &out); err == nil {
+		t.Fatal("Expected run a to fail when it runs out of input")
+	}
+
+	out.Reset()
+	if err := memdiffCmd([]string{"-stop-at-input", "-b-input", "5", filename}, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"a: 0 steps, out of input at 0000, b: 3 steps", "0007-0008 (2 words)"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Expected output to contain %q, got:\n%s", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"adventofcode/intcode"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const replHelp = `commands:
+  load <file>             load a program and reset
+  patch <addr>=<value>... patch the program and the running memory
+  reset                   restart the patched program, dropping queued input
+  run [max]               run until halted, waiting for input, or max steps
+  step [n]                execute n instructions (default 1)
+  input <value>...        queue input values
+  input ascii <text>      queue text followed by a newline
+  output [ascii]          show every output so far
+  regs                    show IP, relative base and step count
+  mem <addr> [n]          show n words of memory (default 8)
+  dis [addr] [n]          disassemble n instructions (default at IP, 10)
+  snapshot <name>         save the current state
+  restore <name>          go back to a saved state
+  undo                    undo the last command that changed state
+  save <file>             write the patched program (.lst for a listing)
+  help                    show this help
+  quit                    exit
+`
+
+const maxUndo = 100
+
+var (
+	errHalted    = errors.New("halted")
+	errNeedInput = errors.New("waiting for input")
+	errNoProgram = errors.New("no program loaded")
+)
+
+type snapshot struct {
+	program []int
+	memory  []int
+	ip      int
+	relBase int
+	steps   int
+	halted  bool
+	inputs  []int
+	outputs []int
+}
+
+type session struct {
+	out       io.Writer
+	program   intcode.IntcodeProgram
+	icc       *intcode.IntCodeComputer
+	inputs    []int
+	outputs   []int
+	undo      []snapshot
+	snapshots map[string]snapshot
+}
+
+func repl(args []string, in io.Reader, out io.Writer) error {
+	s := &session{
+		out:       out,
+		snapshots: make(map[string]snapshot),
+	}
+
+	if len(args) > 0 {
+		if err := s.load(args[0]); err != nil {
+			return err
+		}
+	}
+
+	commands := map[string]func(args []string) error{
+		"load":     s.loadCmd,
+		"patch":    s.patch,
+		"reset":    s.resetCmd,
+		"run":      s.run,
+		"step":     s.stepCmd,
+		"input":    s.input,
+		"output":   s.output,
+		"regs":     s.regs,
+		"mem":      s.mem,
+		"dis":      s.dis,
+		"snapshot": s.snapshot,
+		"restore":  s.restore,
+		"undo":     s.undoCmd,
+		"save":     s.save,
+	}
+	mutating := map[string]bool{"load": true, "patch": true, "reset": true, "run": true, "step": true, "input": true, "restore": true}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		name, args := fields[0], fields[1:]
+		switch name {
+		case "quit", "exit":
+			return nil
+		case "help", "?":
+			fmt.Fprint(out, replHelp)
+			continue
+		}
+
+		cmd, ok := commands[name]
+		if !ok {
+			fmt.Fprintf(out, "unknown command %q, try help\n", name)
+			continue
+		}
+		if s.icc == nil && name != "load" {
+			fmt.Fprintln(out, errNoProgram)
+			continue
+		}
+
+		// Commands that fail leave the session unchanged, so only
+		// successful ones can be undone.
+		var before snapshot
+		undoable := mutating[name] && s.icc != nil
+		if undoable {
+			before = s.take()
+		}
+		if err := cmd(args); err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+		if undoable {
+			s.undo = append(s.undo, before)
+			if len(s.undo) > maxUndo {
+				s.undo = s.undo[1:]
+			}
+		}
+	}
+}
+
+func (s *session) load(filename string) error {
+	program, err := intcode.LoadFile(filename)
+	if err != nil {
+		return err
+	}
+	s.program = program
+	s.reset()
+	fmt.Fprintf(s.out, "loaded %d words from %s\n", len(program), filename)
+	return nil
+}
+
+func (s *session) reset() {
+	icc := intcode.NewIntCodeComputer(intcode.CopyIntcodeProgram(s.program))
+	icc.InputChannel = make(chan int, 1)
+	icc.OutputChannel = make(chan int, 1)
+	icc.DoneChannel = make(chan bool, 1)
+	s.icc = icc
+	s.inputs = nil
+	s.outputs = nil
+}
+
+func (s *session) take() snapshot {
+	return snapshot{
+		program: intcode.CopyIntcodeProgram(s.program),
+		memory:  intcode.CopyIntcodeProgram(s.icc.Program),
+		ip:      s.icc.IP,
+		relBase: s.icc.RelBase,
+		steps:   s.icc.Steps,
+		halted:  s.icc.Halted,
+		inputs:  append([]int{}, s.inputs...),
+		outputs: append([]int{}, s.outputs...),
+	}
+}
+
+func (s *session) apply(snap snapshot) {
+	s.program = intcode.CopyIntcodeProgram(snap.program)
+	s.reset()
+	s.icc.Program = intcode.CopyIntcodeProgram(snap.memory)
+	s.icc.IP = snap.ip
+	s.icc.RelBase = snap.relBase
+	s.icc.Steps = snap.steps
+	s.icc.Halted = snap.halted
+	s.inputs = append([]int{}, snap.inputs...)
+	s.outputs = append([]int{}, snap.outputs...)
+}
+
+// step executes one instruction, feeding it queued input if it needs some.
+// Instructions that cannot run, such as one cut off by the end of memory or
+// one using a negative address, return an error without changing state.
+func (s *session) step() error {
+	icc := s.icc
+	if icc.Halted || icc.IP >= len(icc.Program) {
+		return errHalted
+	}
+	if in := intcode.Decode(icc.Program, icc.IP); in.Data {
+		return fmt.Errorf("invalid instruction %d at %04d", icc.Program[icc.IP], icc.IP)
+	}
+	if err := s.execute(); err != nil {
+		return err
+	}
+
+	select {
+	case o := <-icc.OutputChannel:
+		s.outputs = append(s.outputs, o)
+		fmt.Fprintf(s.out, "out: %d\n", o)
+	default:
+	}
+	select {
+	case <-icc.DoneChannel:
+	default:
+	}
+	return nil
+}
+
+// execute steps the computer, turning a panic into an error. Step only
+// panics on a negative address, before it writes memory or moves IP, so a
+// recovered instruction has not changed the computer. Input is only taken
+// from the queue once the instruction has run.
+func (s *session) execute() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("fault at %04d: %v", s.icc.IP, r)
+		}
+	}()
+	if s.icc.NeedsInput() {
+		if len(s.inputs) == 0 {
+			return errNeedInput
+		}
+		s.icc.InputChannel <- s.inputs[0]
+		s.icc.Step()
+		s.inputs = s.inputs[1:]
+		return nil
+	}
+	s.icc.Step()
+	return nil
+}
+
+// steps executes up to max instructions, or until the program stops when max
+// is 0. It only returns an error if not even one instruction could run, and
+// so nothing changed.
+func (s *session) steps(max int) error {
+	n := 0
+	var err error
+	for ; max == 0 || n < max; n++ {
+		if err = s.step(); err != nil {
+			break
+		}
+	}
+	switch {
+	case err == nil:
+		fmt.Fprintf(s.out, "stopped after %d steps at %04d\n", n, s.icc.IP)
+	case n == 0:
+		return err
+	default:
+		fmt.Fprintf(s.out, "%v after %d steps at %04d\n", err, n, s.icc.IP)
+	}
+	return nil
+}
+
+func (s *session) loadCmd(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: load <file>")
+	}
+	return s.load(args[0])
+}
+
+func (s *session) patch(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: patch <addr>=<value>...")
+	}
+	patches := make([][2]int, len(args))
+	for i, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid patch %q", arg)
+		}
+		addr, err := strconv.Atoi(parts[0])
+		if err != nil || addr < 0 {
+			return fmt.Errorf("invalid address %q", parts[0])
+		}
+		if err := s.checkRange(addr, 1); err != nil {
+			return err
+		}
+		value, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid value %q", parts[1])
+		}
+		patches[i] = [2]int{addr, value}
+	}
+
+	for _, p := range patches {
+		s.program = grow(s.program, p[0])
+		s.program[p[0]] = p[1]
+		s.icc.Program = grow(s.icc.Program, p[0])
+		s.icc.Program[p[0]] = p[1]
+	}
+	return nil
+}
+
+// checkRange rejects n words from addr unless they end within
+// intcode.MaxGrowth words past the end of memory.
+func (s *session) checkRange(addr, n int) error {
+	if limit := len(s.icc.Program) + intcode.MaxGrowth; addr > limit || n > limit-addr {
+		return fmt.Errorf("%d words from %d end more than %d past the end of memory", n, addr, intcode.MaxGrowth)
+	}
+	return nil
+}
+
+func grow(p []int, addr int) []int {
+	if addr < len(p) {
+		return p
+	}
+	n := make([]int, addr+1)
+	copy(n, p)
+	return n
+}
+
+func (s *session) resetCmd(args []string) error {
+	s.reset()
+	return nil
+}
+
+func (s *session) run(args []string) error {
+	max, err := intArg(args, 0, 0)
+	if err != nil {
+		return err
+	}
+	if max < 0 {
+		return fmt.Errorf("invalid max %d", max)
+	}
+	return s.steps(max)
+}
+
+func (s *session) stepCmd(args []string) error {
+	n, err := intArg(args, 0, 1)
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return fmt.Errorf("invalid number of steps %d", n)
+	}
+	if err := s.steps(n); err != nil {
+		return err
+	}
+	return s.dis(nil)
+}
+
+func (s *session) input(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: input <value>... | input ascii <text>")
+	}
+	var values []int
+	if args[0] == "ascii" {
+		for _, r := range strings.Join(args[1:], " ") + "\n" {
+			values = append(values, int(r))
+		}
+	} else {
+		for _, arg := range args {
+			v, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid input %q", arg)
+			}
+			values = append(values, v)
+		}
+	}
+	s.inputs = append(s.inputs, values...)
+	fmt.Fprintf(s.out, "%d inputs queued\n", len(s.inputs))
+	return nil
+}
+
+func (s *session) output(args []string) error {
+	if len(args) > 0 && args[0] == "ascii" {
+		for _, o := range s.outputs {
+			if o < 128 {
+				fmt.Fprintf(s.out, "%c", o)
+			} else {
+				fmt.Fprintf(s.out, "[%d]", o)
+			}
+		}
+		fmt.Fprintln(s.out)
+		return nil
+	}
+	for i, o := range s.outputs {
+		fmt.Fprintf(s.out, "%4d: %d\n", i, o)
+	}
+	return nil
+}
+
+func (s *session) regs(args []string) error {
+	status := "running"
+	if s.icc.Halted {
+		status = "halted"
+	} else if s.icc.NeedsInput() && len(s.inputs) == 0 {
+		status = "waiting for input"
+	}
+	fmt.Fprintf(s.out, "IP=%04d RB=%d steps=%d inputs=%d outputs=%d %s\n", s.icc.IP, s.icc.RelBase, s.icc.Steps, len(s.inputs), len(s.outputs), status)
+	return nil
+}
+
+func (s *session) mem(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: mem <addr> [n]")
+	}
+	addr, err := intArg(args, 0, 0)
+	if err != nil {
+		return err
+	}
+	n, err := intArg(args, 1, 8)
+	if err != nil {
+		return err
+	}
+	if addr < 0 || n < 0 {
+		return errors.New("negative address or count")
+	}
+	if err := s.checkRange(addr, n); err != nil {
+		return err
+	}
+	for i := addr; i < addr+n; i++ {
+		fmt.Fprintf(s.out, "%04d: %d\n", i, s.icc.MemGet(i))
+	}
+	return nil
+}
+
+func (s *session) dis(args []string) error {
+	addr, err := intArg(args, 0, s.icc.IP)
+	if err != nil {
+		return err
+	}
+	n, err := intArg(args, 1, 10)
+	if err != nil {
+		return err
+	}
+	if addr < 0 || n < 0 {
+		return errors.New("negative address or count")
+	}
+	for i := 0; i < n && addr < len(s.icc.Program); i++ {
+		in := intcode.Decode(s.icc.Program, addr)
+		marker := " "
+		if addr == s.icc.IP {
+			marker = ">"
+		}
+		fmt.Fprintf(s.out, "%s %04d: %s\n", marker, addr, in)
+		addr += len(in.Words)
+	}
+	return nil
+}
+
+func (s *session) snapshot(args []string) error {
+	if len(args) != 1 {
+		names := make([]string, 0, len(s.snapshots))
+		for name := range s.snapshots {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(s.out, "snapshots: %s\n", strings.Join(names, " "))
+		return nil
+	}
+	s.snapshots[args[0]] = s.take()
+	return nil
+}
+
+func (s *session) restore(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: restore <name>")
+	}
+	snap, ok := s.snapshots[args[0]]
+	if !ok {
+		return fmt.Errorf("no snapshot %q", args[0])
+	}
+	s.apply(snap)
+	return nil
+}
+
+func (s *session) undoCmd(args []string) error {
+	if len(s.undo) == 0 {
+		return errors.New("nothing to undo")
+	}
+	s.apply(s.undo[len(s.undo)-1])
+	s.undo = s.undo[:len(s.undo)-1]
+	return s.regs(nil)
+}
+
+func (s *session) save(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: save <file>")
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(args[0], ".lst") {
+		err = intcode.WriteListing(f, s.program)
+	} else {
+		words := make([]string, len(s.program))
+		for i, v := range s.program {
+			words[i] = strconv.Itoa(v)
+		}
+		_, err = fmt.Fprintln(f, strings.Join(words, ","))
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func intArg(args []string, i, def int) (int, error) {
+	if i >= len(args) {
+		return def, nil
+	}
+	v, err := strconv.Atoi(args[i])
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", args[i])
+	}
+	return v, nil
+}
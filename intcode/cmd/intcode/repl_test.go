@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runRepl(t *testing.T, program, script string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "program.txt")
+	if err := os.WriteFile(filename, []byte(program), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := repl([]string{filename}, strings.NewReader(script), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestRepl(t *testing.T) {
+	specs := []struct {
+		name    string
+		program string
+		script  string
+		want    []string
+		notWant []string
+	}{
+		{"negative mem", "99", "mem -1\n", []string{"negative address or count"}, nil},
+		{"negative dis", "99", "dis -1\n", []string{"negative address or count"}, nil},
+		{"truncated instruction", "1101,2", "step\nregs\n", []string{"invalid instruction 1101 at 0000", "IP=0000 RB=0 steps=0"}, nil},
+		{"negative address", "1,-1,0,0,99", "step\nregs\n", []string{"fault at 0000", "IP=0000 RB=0 steps=0"}, nil},
+		{"fault after steps", "1101,1,1,0,1,-1,0,0,99", "run\nregs\n", []string{"after 1 steps at 0004", "IP=0004 RB=0 steps=1"}, nil},
+		{"step zero", "99", "step 0\n", []string{"invalid number of steps 0"}, []string{"stopped"}},
+		{"step negative", "99", "step -1\n", []string{"invalid number of steps -1"}, []string{"stopped"}},
+		{"run negative", "99", "run -1\n", []string{"invalid max -1"}, []string{"stopped"}},
+		{"huge patch", "99", "patch 99999999999999=1\nundo\n", []string{"past the end of memory", "nothing to undo"}, nil},
+		{"huge mem", "99", "mem 0 99999999999999\n", []string{"past the end of memory"}, []string{"0000: 99"}},
+		{"patch past end", "99", "patch 3=7\nmem 2 2\n", []string{"0002: 0", "0003: 7"}, nil},
+		{"failed patch not undoable", "99", "patch 0=5 1=x\nundo\nmem 0 1\n", []string{"invalid value \"x\"", "nothing to undo", "0000: 99"}, nil},
+		{"failed step not undoable", "1101,2", "step\nundo\n", []string{"nothing to undo"}, nil},
+		{"undo step", "104,7,99", "step\nundo\n", []string{"out: 7", "IP=0000 RB=0 steps=0"}, nil},
+		{"input and run", "3,5,4,5,99,0", "input 9\nrun\n", []string{"out: 9", "halted after 3 steps at 0004"}, nil},
+	}
+
+	for _, tt := range specs {
+		t.Run(tt.name, func(t *testing.T) {
+			out := runRepl(t, tt.program, tt.script)
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("Expected output to contain %q, got:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"adventofcode/intcode"
+	"adventofcode/intcode/web"
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var gridModes = map[string]int{
+	"":        web.NoGrid,
+	"tile":    web.TileGrid,
+	"painter": web.PainterGrid,
+}
+
+// webCmd runs a program with a live view in the browser. The program reads
+// the --input values first and then integers from in.
+func webCmd(args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("web", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8019", "loopback address to serve on")
+	grid := fs.String("grid", "", "draw output as a grid: tile (day 13) or painter (day 11)")
+	inputs := fs.String("input", "", "comma separated inputs read before standard input")
+	paused := fs.Bool("paused", false, "pause before the first instruction")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: intcode web [flags] <program>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one program")
+	}
+
+	mode, ok := gridModes[*grid]
+	if !ok {
+		return fmt.Errorf("unknown grid %q", *grid)
+	}
+	var values []int
+	for _, s := range splitList(*inputs) {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid input %q", s)
+		}
+		values = append(values, v)
+	}
+	program, err := intcode.LoadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	icc := intcode.NewIntCodeComputer(program)
+	icc.DoneChannel = make(chan bool, 1)
+	dbg := intcode.NewDebugger(icc)
+	server := web.New(icc, dbg, mode)
+	if *paused {
+		dbg.Pause()
+	}
+
+	go feed(icc.InputChannel, values, in, out)
+	go func() {
+		for range icc.OutputChannel {
+		}
+	}()
+	go func() {
+		icc.Run()
+		fmt.Fprintf(out, "halted after %d steps\n", icc.Steps)
+	}()
+
+	fmt.Fprintf(out, "serving %s on http://%s/\n", fs.Arg(0), *addr)
+	return server.ListenAndServe(*addr)
+}
+
+// feed sends values to c, then every integer read from in.
+func feed(c chan<- int, values []int, in io.Reader, out io.Writer) {
+	for _, v := range values {
+		c <- v
+	}
+	scanner := bufio.NewScanner(in)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		v, err := strconv.Atoi(scanner.Text())
+		if err != nil {
+			fmt.Fprintf(out, "ignoring invalid input %q\n", scanner.Text())
+			continue
+		}
+		c <- v
+	}
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,205 @@
+package intcode
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrRunning is returned when inspecting a computer that is not stopped.
+var ErrRunning = errors.New("intcode: computer is running")
+
+// State is a snapshot of a computer's registers.
+type State struct {
+	IP      int
+	RelBase int
+	Steps   int
+	Paused  bool
+	Stopped bool
+	Halted  bool
+}
+
+// Debugger pauses, steps and resumes a computer from other goroutines. It
+// stops the computer between instructions, so while it is stopped the
+// computer's memory can be safely read and written with Do.
+type Debugger struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	icc         *IntCodeComputer
+	state       State
+	steps       int
+	stopped     chan struct{}
+	halted      chan struct{}
+	breakpoints map[int]bool
+}
+
+// NewDebugger attaches a debugger to icc. It must be called before the
+// program starts running.
+func NewDebugger(icc *IntCodeComputer) *Debugger {
+	d := &Debugger{
+		icc:         icc,
+		stopped:     make(chan struct{}),
+		halted:      make(chan struct{}),
+		breakpoints: make(map[int]bool),
+	}
+	d.cond = sync.NewCond(&d.mu)
+	icc.AddHook(d.hook)
+	return d
+}
+
+func (d *Debugger) hook(icc *IntCodeComputer, e Event) {
+	switch e.Kind {
+	case StepEvent:
+	case HaltEvent:
+		d.mu.Lock()
+		select {
+		case <-d.halted:
+		default:
+			close(d.halted)
+		}
+		d.update()
+		d.state.Halted = true
+		d.notify()
+		d.mu.Unlock()
+		return
+	default:
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.update()
+	if d.breakpoints[d.state.IP] {
+		d.state.Paused = true
+		d.steps = 0
+	}
+	if d.steps > 0 {
+		d.steps--
+		return
+	}
+	if !d.state.Paused {
+		return
+	}
+
+	d.state.Stopped = true
+	d.notify()
+	for d.state.Paused && d.steps == 0 {
+		d.cond.Wait()
+	}
+	d.state.Stopped = false
+	if d.steps > 0 {
+		d.steps--
+	}
+}
+
+func (d *Debugger) update() {
+	d.state.IP = d.icc.IP
+	d.state.RelBase = d.icc.RelBase
+	d.state.Steps = d.icc.Steps
+	d.state.Halted = d.icc.Halted
+}
+
+func (d *Debugger) notify() {
+	close(d.stopped)
+	d.stopped = make(chan struct{})
+}
+
+// State returns the registers as of the last instruction boundary.
+func (d *Debugger) State() State {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
+// Halted returns a channel that is closed once the program halts.
+func (d *Debugger) Halted() <-chan struct{} {
+	return d.halted
+}
+
+// Pause stops the computer before its next instruction.
+func (d *Debugger) Pause() {
+	d.mu.Lock()
+	d.state.Paused = true
+	d.mu.Unlock()
+}
+
+// Resume lets a paused computer run freely.
+func (d *Debugger) Resume() {
+	d.mu.Lock()
+	d.state.Paused = false
+	d.steps = 0
+	d.cond.Broadcast()
+	d.mu.Unlock()
+}
+
+// Step lets a paused computer execute n more instructions before stopping
+// again. It pauses a running computer.
+func (d *Debugger) Step(n int) {
+	d.mu.Lock()
+	d.state.Paused = true
+	d.steps = n
+	d.state.Stopped = false
+	d.cond.Broadcast()
+	d.mu.Unlock()
+}
+
+// Wait blocks until the computer stops or halts, or the timeout passes. It
+// reports whether the computer is stopped or halted.
+func (d *Debugger) Wait(timeout time.Duration) (State, bool) {
+	d.mu.Lock()
+	if d.state.Stopped || d.state.Halted {
+		defer d.mu.Unlock()
+		return d.state, true
+	}
+	stopped := d.stopped
+	d.mu.Unlock()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+	}
+
+	s := d.State()
+	return s, s.Stopped || s.Halted
+}
+
+// SetBreakpoint stops the computer whenever it is about to execute the
+// instruction at addr.
+func (d *Debugger) SetBreakpoint(addr int) {
+	d.mu.Lock()
+	d.breakpoints[addr] = true
+	d.mu.Unlock()
+}
+
+func (d *Debugger) ClearBreakpoint(addr int) {
+	d.mu.Lock()
+	delete(d.breakpoints, addr)
+	d.mu.Unlock()
+}
+
+// Breakpoints returns the breakpoint addresses in ascending order.
+func (d *Debugger) Breakpoints() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	addrs := make([]int, 0, len(d.breakpoints))
+	for addr := range d.breakpoints {
+		addrs = append(addrs, addr)
+	}
+	sort.Ints(addrs)
+	return addrs
+}
+
+// Do calls f with the computer while it is stopped or halted, returning
+// ErrRunning otherwise.
+func (d *Debugger) Do(f func(icc *IntCodeComputer)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.state.Stopped && !d.state.Halted {
+		return ErrRunning
+	}
+	f(d.icc)
+	d.update()
+	return nil
+}
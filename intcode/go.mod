@@ -1,3 +0,0 @@
-module adventofcode/intcode
-
-go 1.15
@@ -1,9 +1,8 @@
 package intcode
 
 import (
-	"encoding/json"
-	"io/ioutil"
 	"strconv"
+	"sync"
 )
 
 type IntcodeProgram []int
@@ -58,15 +57,41 @@ func (p *Param) Value(icc *IntCodeComputer) int {
 	}
 }
 
+// Event kinds reported to hooks.
+const (
+	StepEvent   = iota // Before the instruction at IP executes
+	WriteEvent         // Memory at Addr was set to Value
+	InputEvent         // Value was read from the input channel
+	OutputEvent        // Value is about to be sent on the output channel
+	HaltEvent          // The program halted
+)
+
+type Event struct {
+	Kind  int
+	IP    int
+	Addr  int
+	Value int
+}
+
+// Hook observes a computer as it runs. Hooks are called on the goroutine
+// running the program, so a hook may block to pause execution.
+type Hook func(icc *IntCodeComputer, e Event)
+
 type IntCodeComputer struct {
 	Name          string
 	PhaseSetting  int
 	Program       []int
+	IP            int
 	RelBase       int
+	Steps         int
+	Halted        bool
 	InputChannel  chan int
 	OutputChannel chan int
 	DoneChannel   chan bool
 	RequestInput  bool
+	hooks         []Hook
+	stop          chan struct{}
+	stopOnce      sync.Once
 }
 
 func NewIntCodeComputer(program []int) *IntCodeComputer {
@@ -75,11 +100,31 @@ func NewIntCodeComputer(program []int) *IntCodeComputer {
 		InputChannel:  make(chan int),
 		OutputChannel: make(chan int),
 		DoneChannel:   make(chan bool),
+		stop:          make(chan struct{}),
+	}
+}
+
+// Stop makes the program give up before its next instruction, or while it is
+// waiting to send or receive, so the goroutine running it can exit. It may be
+// called from any goroutine, any number of times.
+func (icc *IntCodeComputer) Stop() {
+	icc.stopOnce.Do(func() { close(icc.stop) })
+}
+
+// AddHook registers h to be called for every event. Hooks must be added
+// before the program starts running.
+func (icc *IntCodeComputer) AddHook(h Hook) {
+	icc.hooks = append(icc.hooks, h)
+}
+
+func (icc *IntCodeComputer) emit(e Event) {
+	for _, h := range icc.hooks {
+		h(icc, e)
 	}
 }
 
 func (icc *IntCodeComputer) MemGet(i int) int {
-	if i > len(icc.Program) {
+	if i >= len(icc.Program) {
 		return 0
 	}
 	return icc.Program[i]
@@ -100,59 +145,113 @@ func (icc *IntCodeComputer) MemSet(p Param, v int) {
 		icc.Program = n
 	}
 	icc.Program[i] = v
+	icc.emit(Event{Kind: WriteEvent, IP: icc.IP, Addr: i, Value: v})
 }
 
+// Run executes the program from the start until it halts.
 func (icc *IntCodeComputer) Run() {
-	for programIndex := 0; programIndex < len(icc.Program); {
-		value := icc.Program[programIndex]
-		opcode, params := parseInstruction(value)
+	icc.IP = 0
+	icc.Steps = 0
+	icc.Halted = false
+	for icc.Step() {
+	}
+}
 
-		for x := 0; x < opcodeArity[opcode]; x++ {
-			params[x].ValueOrOffset = icc.Program[programIndex+x+1]
-		}
+// Step executes the instruction at IP. It returns false once the program has
+// halted, run off the end of memory or been stopped.
+func (icc *IntCodeComputer) Step() bool {
+	if icc.Halted || icc.IP >= len(icc.Program) {
+		return false
+	}
+	select {
+	case <-icc.stop:
+		return false
+	default:
+	}
 
-		programIndex += opcodeArity[opcode] + 1
+	icc.emit(Event{Kind: StepEvent, IP: icc.IP})
 
-		switch opcode {
-		case add:
-			icc.MemSet(params[2], params[0].Value(icc)+params[1].Value(icc))
-		case mult:
-			icc.MemSet(params[2], params[0].Value(icc)*params[1].Value(icc))
-		case jumpIfTrue:
-			if params[0].Value(icc) != 0 {
-				programIndex = params[1].Value(icc)
-			}
-		case jumpIfFalse:
-			if params[0].Value(icc) == 0 {
-				programIndex = params[1].Value(icc)
-			}
-		case lessThan:
-			if params[0].Value(icc) < params[1].Value(icc) {
-				icc.MemSet(params[2], 1)
-			} else {
-				icc.MemSet(params[2], 0)
-			}
-		case equals:
-			if params[0].Value(icc) == params[1].Value(icc) {
-				icc.MemSet(params[2], 1)
-			} else {
-				icc.MemSet(params[2], 0)
-			}
-		case setRelBase:
-			icc.RelBase += params[0].Value(icc)
-		case input:
-			// Check to see if anyone is waiting
-			if icc.RequestInput {
-				icc.InputChannel <- 0
+	value := icc.Program[icc.IP]
+	opcode, params := parseInstruction(value)
+
+	for x := 0; x < opcodeArity[opcode]; x++ {
+		params[x].ValueOrOffset = icc.Program[icc.IP+x+1]
+	}
+
+	next := icc.IP + opcodeArity[opcode] + 1
+
+	switch opcode {
+	case add:
+		icc.MemSet(params[2], params[0].Value(icc)+params[1].Value(icc))
+	case mult:
+		icc.MemSet(params[2], params[0].Value(icc)*params[1].Value(icc))
+	case jumpIfTrue:
+		if params[0].Value(icc) != 0 {
+			next = params[1].Value(icc)
+		}
+	case jumpIfFalse:
+		if params[0].Value(icc) == 0 {
+			next = params[1].Value(icc)
+		}
+	case lessThan:
+		if params[0].Value(icc) < params[1].Value(icc) {
+			icc.MemSet(params[2], 1)
+		} else {
+			icc.MemSet(params[2], 0)
+		}
+	case equals:
+		if params[0].Value(icc) == params[1].Value(icc) {
+			icc.MemSet(params[2], 1)
+		} else {
+			icc.MemSet(params[2], 0)
+		}
+	case setRelBase:
+		icc.RelBase += params[0].Value(icc)
+	case input:
+		// Check to see if anyone is waiting
+		if icc.RequestInput {
+			select {
+			case icc.InputChannel <- 0:
+			case <-icc.stop:
+				return false
 			}
-			icc.MemSet(params[0], <-icc.InputChannel)
-		case output:
-			icc.OutputChannel <- params[0].Value(icc)
-		case halt:
-			icc.DoneChannel <- true
-			return
 		}
+		var v int
+		select {
+		case v = <-icc.InputChannel:
+		case <-icc.stop:
+			return false
+		}
+		icc.emit(Event{Kind: InputEvent, IP: icc.IP, Value: v})
+		icc.MemSet(params[0], v)
+	case output:
+		v := params[0].Value(icc)
+		icc.emit(Event{Kind: OutputEvent, IP: icc.IP, Value: v})
+		select {
+		case icc.OutputChannel <- v:
+		case <-icc.stop:
+			return false
+		}
+	case halt:
+		icc.Steps++
+		icc.Halted = true
+		icc.emit(Event{Kind: HaltEvent, IP: icc.IP})
+		select {
+		case icc.DoneChannel <- true:
+		case <-icc.stop:
+		}
+		return false
 	}
+
+	icc.IP = next
+	icc.Steps++
+	return true
+}
+
+// NeedsInput reports whether the next instruction reads from the input
+// channel.
+func (icc *IntCodeComputer) NeedsInput() bool {
+	return !icc.Halted && icc.IP < len(icc.Program) && icc.Program[icc.IP]%100 == input
 }
 
 func parseInstruction(value int) (int, []Param) {
@@ -185,16 +284,12 @@ func reverse(s string) string {
 	return string(runes)
 }
 
+// ReadIntcodeProgram loads filename with LoadFile, panicking on any error.
 func ReadIntcodeProgram(filename string) IntcodeProgram {
-	line, err := ioutil.ReadFile(filename)
+	icp, err := LoadFile(filename)
 	if err != nil {
 		panic(err)
 	}
-
-	var icp IntcodeProgram
-	if err := json.Unmarshal([]byte("["+string(line)+"]"), &icp); err != nil {
-		panic(err)
-	}
 	return icp
 }
 
@@ -0,0 +1,36 @@
+package intcode
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStop(t *testing.T) {
+	specs := map[string][]int{
+		"input":         {3, 0, 99},
+		"request input": {3, 0, 99},
+		"output":        {104, 1, 99},
+		"halt":          {99},
+		"loop":          {1105, 1, 0},
+	}
+
+	for name, program := range specs {
+		t.Run(name, func(t *testing.T) {
+			icc := NewIntCodeComputer(program)
+			icc.RequestInput = name == "request input"
+			done := make(chan struct{})
+			go func() {
+				icc.Run()
+				close(done)
+			}()
+
+			icc.Stop()
+			icc.Stop()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Expected the program to stop")
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package intcode
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError reports a malformed token and where it was found. Lines and
+// columns are 1-based, and columns count runes.
+type SyntaxError struct {
+	Line  int
+	Col   int
+	Token string
+	Msg   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%d:%d: %s: %q", e.Line, e.Col, e.Msg, e.Token)
+}
+
+type token struct {
+	text string
+	col  int
+}
+
+func (t token) errorf(line int, format string, args ...interface{}) error {
+	return &SyntaxError{Line: line, Col: t.col, Token: t.text, Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokenize splits a line on commas and whitespace, dropping anything after a
+// '#'. Columns are 1-based and count runes.
+func tokenize(line string) []token {
+	var tokens []token
+	start, startCol, col := -1, 0, 0
+	for i, r := range line + " " {
+		col++
+		if r == '#' || r == ',' || unicode.IsSpace(r) {
+			if start != -1 {
+				tokens = append(tokens, token{text: line[start:i], col: startCol})
+				start = -1
+			}
+			if r == '#' {
+				break
+			}
+			continue
+		}
+		if start == -1 {
+			start, startCol = i, col
+		}
+	}
+	return tokens
+}
+
+// Load reads an Intcode program from r. Gzip compressed input is detected and
+// decompressed. The program may be the puzzle's comma separated integers
+// (with any mix of commas, whitespace, newlines and '#' comments), the
+// assembler text format, or a disassembler listing.
+func Load(r io.Reader) (IntcodeProgram, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAssembly(string(src)) {
+		return Assemble(string(src))
+	}
+
+	program := IntcodeProgram{}
+	for i, line := range strings.Split(string(src), "\n") {
+		for _, t := range tokenize(line) {
+			v, err := strconv.Atoi(t.text)
+			if err != nil {
+				return nil, t.errorf(i+1, "invalid integer")
+			}
+			program = append(program, v)
+		}
+	}
+	return program, nil
+}
+
+// LoadFile loads an Intcode program from filename, or from stdin when
+// filename is "-".
+func LoadFile(filename string) (IntcodeProgram, error) {
+	if filename == "-" {
+		return Load(os.Stdin)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	program, err := Load(f)
+	if err, ok := err.(*SyntaxError); ok {
+		return nil, fmt.Errorf("%s:%w", filename, err)
+	}
+	return program, err
+}
+
+// isAssembly reports whether the first token of the source is a mnemonic or
+// a listing address rather than an integer.
+func isAssembly(src string) bool {
+	for _, line := range strings.Split(src, "\n") {
+		tokens := tokenize(line)
+		if len(tokens) == 0 {
+			continue
+		}
+		first := tokens[0].text
+		r, _ := utf8.DecodeRuneInString(first)
+		return strings.HasSuffix(first, ":") || unicode.IsLetter(r)
+	}
+	return false
+}
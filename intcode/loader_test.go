@@ -0,0 +1,113 @@
+package intcode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte("1,0,0,0,99\n"))
+	w.Close()
+
+	specs := map[string]string{
+		"plain":      "1,0,0,0,99",
+		"newline":    "1,0,0,0,99\n  \t\n",
+		"trailing":   "1,0,0,0,99,\n",
+		"multiline":  "1,0,\n0,0\n99",
+		"comments":   "# gravity assist\n1,0,0,0, # add\n99 # done\n",
+		"whitespace": "1 0 0 0 99",
+		"assembly":   "add [0], [0], [0]\nhlt\n",
+		"listing":    "0000: add [0], [0], [0] # comment\n0004: hlt\n",
+		"gzip":       gz.String(),
+	}
+
+	for name, src := range specs {
+		t.Run(name, func(t *testing.T) {
+			p, err := Load(strings.NewReader(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equal(p, []int{1, 0, 0, 0, 99}) {
+				t.Errorf("Expected [1 0 0 0 99], got %v", p)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	specs := []struct {
+		Input string
+		Line  int
+		Col   int
+	}{
+		{"1,0,0,0,99\n1,x2,3", 2, 3},
+		{"add [0], [0]\n", 1, 1},
+		{"0000: hlt\n0002: hlt", 2, 1},
+		{"hlt\nadd [0], [0], [rb+x]", 2, 15},
+		{"add 1, 2, 3", 1, 11},
+		{"in 5", 1, 4},
+		{"# π ≈ 3\n1, π, 0", 2, 4},
+	}
+
+	for _, spec := range specs {
+		_, err := Load(strings.NewReader(spec.Input))
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Fatalf("Input: %q. Expected a SyntaxError, got %v", spec.Input, err)
+		}
+		if se.Line != spec.Line || se.Col != spec.Col {
+			t.Errorf("Input: %q. Expected %d:%d, got %d:%d", spec.Input, spec.Line, spec.Col, se.Line, se.Col)
+		}
+	}
+}
+
+func TestIsAssembly(t *testing.T) {
+	specs := map[string]bool{
+		"1,0,0,0,99":      false,
+		"\n# comment\n-1": false,
+		"\u22121, 2":      false,
+		"add [0], 1, [0]": true,
+		"πr [0]":          true,
+		"0000: hlt":       true,
+	}
+
+	for src, expected := range specs {
+		if actual := isAssembly(src); actual != expected {
+			t.Errorf("Input: %q. Expected %v, got %v", src, expected, actual)
+		}
+	}
+}
+
+func TestListingRoundTrip(t *testing.T) {
+	program := []int{3, 9, 1008, 9, 10, 9, 4, 9, 99, -1, 8, 109, 19, 204, -34, 10099, 1105, 1, 0, 11101, 1, 2, 3}
+
+	var listing bytes.Buffer
+	if err := WriteListing(&listing, program); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(&listing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(p, program) {
+		t.Errorf("Expected %v, got %v", program, p)
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+// Package memdiff compares the memory of two Intcode computers and reports
+// where each differing address was last written.
+package memdiff
+
+import (
+	"adventofcode/intcode"
+	"fmt"
+	"io"
+)
+
+// Write is the last write to an address: the instruction count (1-based) and
+// the address of the instruction that made it.
+type Write struct {
+	Step int
+	IP   int
+}
+
+// Tracker records the last write to every address of a computer.
+type Tracker struct {
+	writes map[int]Write
+}
+
+// Track attaches a tracker to icc. It must be called before the program
+// starts running.
+func Track(icc *intcode.IntCodeComputer) *Tracker {
+	t := &Tracker{writes: make(map[int]Write)}
+	icc.AddHook(func(icc *intcode.IntCodeComputer, e intcode.Event) {
+		if e.Kind == intcode.WriteEvent {
+			t.writes[e.Addr] = Write{Step: icc.Steps + 1, IP: e.IP}
+		}
+	})
+	return t
+}
+
+// LastWrite returns the last write to addr, if there was one.
+func (t *Tracker) LastWrite(addr int) (Write, bool) {
+	if t == nil {
+		return Write{}, false
+	}
+	w, ok := t.writes[addr]
+	return w, ok
+}
+
+type Change struct {
+	Addr int
+	A    int
+	B    int
+	// Last writes on each side, nil if the address still holds its initial
+	// value or no tracker was given.
+	WriteA *Write
+	WriteB *Write
+}
+
+// Region is a run of contiguous changed addresses, [Start, End).
+type Region struct {
+	Start   int
+	End     int
+	Changes []Change
+}
+
+// Diff compares two memory snapshots, such as IntCodeComputer.Program after
+// two runs, grouping changed addresses into contiguous regions. Memory past
+// the end of the shorter snapshot reads as zero. Either tracker may be nil.
+func Diff(a, b []int, ta, tb *Tracker) []Region {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+
+	var regions []Region
+	for addr := 0; addr < n; addr++ {
+		va, vb := at(a, addr), at(b, addr)
+		if va == vb {
+			continue
+		}
+
+		c := Change{Addr: addr, A: va, B: vb}
+		if w, ok := ta.LastWrite(addr); ok {
+			c.WriteA = &w
+		}
+		if w, ok := tb.LastWrite(addr); ok {
+			c.WriteB = &w
+		}
+
+		if len(regions) == 0 || regions[len(regions)-1].End != addr {
+			regions = append(regions, Region{Start: addr})
+		}
+		r := &regions[len(regions)-1]
+		r.End = addr + 1
+		r.Changes = append(r.Changes, c)
+	}
+	return regions
+}
+
+func at(memory []int, addr int) int {
+	if addr < len(memory) {
+		return memory[addr]
+	}
+	return 0
+}
+
+// Print writes regions in a human readable report.
+func Print(w io.Writer, regions []Region) error {
+	for _, r := range regions {
+		if _, err := fmt.Fprintf(w, "%04d-%04d (%d words)\n", r.Start, r.End-1, r.End-r.Start); err != nil {
+			return err
+		}
+		for _, c := range r.Changes {
+			if _, err := fmt.Fprintf(w, "  %04d: %12d -> %-12d  a: %s  b: %s\n", c.Addr, c.A, c.B, describe(c.WriteA), describe(c.WriteB)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func describe(w *Write) string {
+	if w == nil {
+		return "initial"
+	}
+	return fmt.Sprintf("step %d at %04d", w.Step, w.IP)
+}
@@ -0,0 +1,38 @@
+package memdiff
+
+import (
+	"adventofcode/intcode"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	run := func(noun int) (*intcode.IntCodeComputer, *Tracker) {
+		// add [noun], [noun], [0]; hlt
+		icc := intcode.NewIntCodeComputer([]int{1, noun, noun, 0, 99, 7})
+		tracker := Track(icc)
+		go icc.Run()
+		<-icc.DoneChannel
+		return icc, tracker
+	}
+
+	a, ta := run(4)
+	b, tb := run(5)
+
+	regions := Diff(a.Program, append(b.Program, 0, 3), ta, tb)
+	if len(regions) != 2 {
+		t.Fatalf("Expected 2 regions, got %+v", regions)
+	}
+
+	if r := regions[0]; r.Start != 0 || r.End != 3 {
+		t.Errorf("Expected the first region to be [0, 3), got [%d, %d)", r.Start, r.End)
+	}
+	if c := regions[0].Changes[0]; c.A != 198 || c.B != 14 || c.WriteA == nil || *c.WriteB != (Write{Step: 1, IP: 0}) {
+		t.Errorf("Expected 198 -> 14 written by step 1 at 0, got %+v", c)
+	}
+	if c := regions[0].Changes[1]; c.WriteA != nil || c.WriteB != nil {
+		t.Errorf("Expected address 1 to hold its initial value, got %+v", c)
+	}
+	if r := regions[1]; r.Start != 7 || r.Changes[0].A != 0 || r.Changes[0].B != 3 {
+		t.Errorf("Expected memory past the end to read as zero, got %+v", r)
+	}
+}
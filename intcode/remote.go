@@ -0,0 +1,313 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"strings"
+	"time"
+)
+
+// ServeDebug serves the debug protocol for icc on addr, as accepted by
+// ListenDebug, until the returned closer is closed. It must be called before
+// the program starts running.
+func (icc *IntCodeComputer) ServeDebug(addr string) (io.Closer, error) {
+	l, err := ListenDebug(addr)
+	if err != nil {
+		return nil, err
+	}
+	go ServeDebugger(l, NewDebugger(icc))
+	return l, nil
+}
+
+// ListenDebug listens on "unix:/path/to/socket", "tcp:host:port" or
+// "host:port". TCP hosts must be loopback addresses.
+func ListenDebug(addr string) (net.Listener, error) {
+	if strings.HasPrefix(addr, "unix:") {
+		return net.Listen("unix", strings.TrimPrefix(addr, "unix:"))
+	}
+
+	addr = strings.TrimPrefix(addr, "tcp:")
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("intcode: %s is not a loopback address", addr)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// ServeDebugger serves the debug protocol for d on every connection accepted
+// from l. The protocol is JSON-RPC 1.0, one object per request, with the
+// methods of DebugService under the name "Intcode", e.g.
+//
+//	{"id": 1, "method": "Intcode.Step", "params": [{"N": 1}]}
+func ServeDebugger(l net.Listener, d *Debugger) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("Intcode", &DebugService{d: d, done: done}); err != nil {
+		return err
+	}
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+type Empty struct{}
+
+type BreakpointArgs struct {
+	Addr int
+}
+
+type StepArgs struct {
+	N int
+	// How long to wait for the computer to stop. Defaults to a second.
+	TimeoutMillis int
+}
+
+// MaxGrowth is how far past the end of memory the debug protocol may read or
+// write, so a client can't make the computer allocate without bound.
+const MaxGrowth = 1 << 20
+
+type MemoryArgs struct {
+	Addr   int
+	Len    int
+	Values []int
+}
+
+type InputArgs struct {
+	Values []int
+}
+
+// DebugService is the debug protocol's RPC receiver.
+type DebugService struct {
+	d    *Debugger
+	done chan struct{}
+}
+
+func (s *DebugService) SetBreakpoint(args BreakpointArgs, reply *[]int) error {
+	s.d.SetBreakpoint(args.Addr)
+	*reply = s.d.Breakpoints()
+	return nil
+}
+
+func (s *DebugService) ClearBreakpoint(args BreakpointArgs, reply *[]int) error {
+	s.d.ClearBreakpoint(args.Addr)
+	*reply = s.d.Breakpoints()
+	return nil
+}
+
+func (s *DebugService) Breakpoints(args Empty, reply *[]int) error {
+	*reply = s.d.Breakpoints()
+	return nil
+}
+
+// Step executes N instructions (at least one) and waits for the computer to
+// stop again.
+func (s *DebugService) Step(args StepArgs, reply *State) error {
+	if args.N < 1 {
+		args.N = 1
+	}
+	s.d.Step(args.N)
+	*reply, _ = s.d.Wait(timeout(args.TimeoutMillis))
+	return nil
+}
+
+// Continue resumes the computer without waiting for it to stop.
+func (s *DebugService) Continue(args Empty, reply *State) error {
+	s.d.Resume()
+	*reply = s.d.State()
+	return nil
+}
+
+// Pause stops the computer before its next instruction and waits for it.
+func (s *DebugService) Pause(args StepArgs, reply *State) error {
+	s.d.Pause()
+	*reply, _ = s.d.Wait(timeout(args.TimeoutMillis))
+	return nil
+}
+
+// Wait waits for the computer to stop at a breakpoint or halt.
+func (s *DebugService) Wait(args StepArgs, reply *State) error {
+	*reply, _ = s.d.Wait(timeout(args.TimeoutMillis))
+	return nil
+}
+
+func (s *DebugService) Registers(args Empty, reply *State) error {
+	*reply = s.d.State()
+	return nil
+}
+
+// ReadMemory reads Len words from Addr. Memory past the end of the program
+// reads as zero, up to MaxGrowth words past it.
+func (s *DebugService) ReadMemory(args MemoryArgs, reply *[]int) error {
+	var err error
+	doErr := s.d.Do(func(icc *IntCodeComputer) {
+		if err = checkRange(icc, args.Addr, args.Len); err != nil {
+			return
+		}
+		values := make([]int, args.Len)
+		for i := range values {
+			values[i] = icc.MemGet(args.Addr + i)
+		}
+		*reply = values
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// WriteMemory writes Values starting at Addr, growing memory as needed up to
+// MaxGrowth words past its end.
+func (s *DebugService) WriteMemory(args MemoryArgs, reply *Empty) error {
+	var err error
+	doErr := s.d.Do(func(icc *IntCodeComputer) {
+		if err = checkRange(icc, args.Addr, len(args.Values)); err != nil {
+			return
+		}
+		if end := args.Addr + len(args.Values); end > len(icc.Program) {
+			n := make([]int, end)
+			copy(n, icc.Program)
+			icc.Program = n
+		}
+		copy(icc.Program[args.Addr:], args.Values)
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// checkRange rejects n words from addr unless they end within MaxGrowth
+// words past the end of memory.
+func checkRange(icc *IntCodeComputer, addr, n int) error {
+	if addr < 0 || n < 0 {
+		return errors.New("intcode: negative address or length")
+	}
+	if limit := len(icc.Program) + MaxGrowth; addr > limit || n > limit-addr {
+		return fmt.Errorf("intcode: %d words from %d end more than %d past the end of memory", n, addr, MaxGrowth)
+	}
+	return nil
+}
+
+// Input queues Values on the computer's input channel. It does not wait for
+// the program to read them. Values still queued when the program halts or
+// the server stops are dropped.
+func (s *DebugService) Input(args InputArgs, reply *Empty) error {
+	go func() {
+		for _, v := range args.Values {
+			select {
+			case s.d.icc.InputChannel <- v:
+			case <-s.d.Halted():
+				return
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func timeout(millis int) time.Duration {
+	if millis <= 0 {
+		return time.Second
+	}
+	return time.Duration(millis) * time.Millisecond
+}
+
+// DebugClient talks the debug protocol to a computer served by
+// ServeDebugger.
+type DebugClient struct {
+	c *rpc.Client
+}
+
+// DialDebugger connects to an address as accepted by ListenDebug.
+func DialDebugger(addr string) (*DebugClient, error) {
+	network := "tcp"
+	if strings.HasPrefix(addr, "unix:") {
+		network, addr = "unix", strings.TrimPrefix(addr, "unix:")
+	}
+	c, err := jsonrpc.Dial(network, strings.TrimPrefix(addr, "tcp:"))
+	if err != nil {
+		return nil, err
+	}
+	return &DebugClient{c: c}, nil
+}
+
+func (c *DebugClient) Close() error {
+	return c.c.Close()
+}
+
+func (c *DebugClient) SetBreakpoint(addr int) ([]int, error) {
+	var reply []int
+	err := c.c.Call("Intcode.SetBreakpoint", BreakpointArgs{Addr: addr}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) ClearBreakpoint(addr int) ([]int, error) {
+	var reply []int
+	err := c.c.Call("Intcode.ClearBreakpoint", BreakpointArgs{Addr: addr}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) Breakpoints() ([]int, error) {
+	var reply []int
+	err := c.c.Call("Intcode.Breakpoints", Empty{}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) Step(n int, timeout time.Duration) (State, error) {
+	var reply State
+	err := c.c.Call("Intcode.Step", StepArgs{N: n, TimeoutMillis: int(timeout / time.Millisecond)}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) Continue() (State, error) {
+	var reply State
+	err := c.c.Call("Intcode.Continue", Empty{}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) Pause(timeout time.Duration) (State, error) {
+	var reply State
+	err := c.c.Call("Intcode.Pause", StepArgs{TimeoutMillis: int(timeout / time.Millisecond)}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) Wait(timeout time.Duration) (State, error) {
+	var reply State
+	err := c.c.Call("Intcode.Wait", StepArgs{TimeoutMillis: int(timeout / time.Millisecond)}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) Registers() (State, error) {
+	var reply State
+	err := c.c.Call("Intcode.Registers", Empty{}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) ReadMemory(addr, n int) ([]int, error) {
+	var reply []int
+	err := c.c.Call("Intcode.ReadMemory", MemoryArgs{Addr: addr, Len: n}, &reply)
+	return reply, err
+}
+
+func (c *DebugClient) WriteMemory(addr int, values ...int) error {
+	return c.c.Call("Intcode.WriteMemory", MemoryArgs{Addr: addr, Values: values}, &Empty{})
+}
+
+func (c *DebugClient) Input(values ...int) error {
+	return c.c.Call("Intcode.Input", InputArgs{Values: values}, &Empty{})
+}
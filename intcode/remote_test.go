@@ -0,0 +1,176 @@
+package intcode
+
+import (
+	"math"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRemoteDebugger(t *testing.T) {
+	// in [9]; add [9], 5, [9]; out [9]; hlt
+	icc := NewIntCodeComputer([]int{3, 9, 1001, 9, 5, 9, 4, 9, 99, 0})
+	d := NewDebugger(icc)
+	d.SetBreakpoint(6)
+
+	addr := "unix:" + filepath.Join(t.TempDir(), "icc.sock")
+	l, err := ListenDebug(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go ServeDebugger(l, d)
+
+	c, err := DialDebugger(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	go icc.Run()
+	if err := c.Input(10); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := c.Wait(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Stopped || s.IP != 6 || s.Steps != 2 {
+		t.Fatalf("Expected to stop at the breakpoint at 6 after 2 steps, got %+v", s)
+	}
+
+	mem, err := c.ReadMemory(9, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mem[0] != 15 {
+		t.Errorf("Expected [9] to be 15, got %d", mem[0])
+	}
+
+	if err := c.WriteMemory(9, 42); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		c.Step(1, time.Second)
+	}()
+	if o := <-icc.OutputChannel; o != 42 {
+		t.Errorf("Expected output 42, got %d", o)
+	}
+
+	if _, err := c.Continue(); err != nil {
+		t.Fatal(err)
+	}
+	<-icc.DoneChannel
+
+	s, err = c.Registers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Halted || s.IP != 8 {
+		t.Errorf("Expected to halt at 8, got %+v", s)
+	}
+}
+
+func TestListenDebugRejectsRemoteHosts(t *testing.T) {
+	if _, err := ListenDebug("tcp:0.0.0.0:0"); err == nil {
+		t.Error("Expected an error listening on all interfaces")
+	}
+}
+
+func TestServeDebug(t *testing.T) {
+	icc := NewIntCodeComputer([]int{99})
+	addr := "unix:" + filepath.Join(t.TempDir(), "icc.sock")
+	closer, err := icc.ServeDebug(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := DialDebugger(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.Registers(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := DialDebugger(addr); err == nil {
+		c.Close()
+		t.Error("Expected no new connections once closed")
+	}
+}
+
+// serveDebug serves a debugger for icc and connects a client to it.
+func serveDebug(t *testing.T, icc *IntCodeComputer) *DebugClient {
+	t.Helper()
+	addr := "unix:" + filepath.Join(t.TempDir(), "icc.sock")
+	closer, err := icc.ServeDebug(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closer.Close() })
+
+	c, err := DialDebugger(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestRemoteMemoryLimits(t *testing.T) {
+	icc := NewIntCodeComputer([]int{99})
+	icc.DoneChannel = make(chan bool, 1)
+	c := serveDebug(t, icc)
+	go icc.Run()
+	if s, err := c.Wait(time.Second); err != nil || !s.Halted {
+		t.Fatalf("Expected to halt, got %+v, %v", s, err)
+	}
+
+	if _, err := c.ReadMemory(0, math.MaxInt); err == nil {
+		t.Error("Expected an error reading MaxInt words")
+	}
+	if _, err := c.ReadMemory(math.MaxInt, 1); err == nil {
+		t.Error("Expected an error reading from MaxInt")
+	}
+	if err := c.WriteMemory(1+MaxGrowth, 1); err == nil {
+		t.Error("Expected an error writing past MaxGrowth")
+	}
+	if mem, err := c.ReadMemory(1, MaxGrowth); err != nil || len(mem) != MaxGrowth {
+		t.Errorf("Expected to read up to MaxGrowth, got %d words, %v", len(mem), err)
+	}
+	if err := c.WriteMemory(MaxGrowth, 7); err != nil {
+		t.Errorf("Expected to write up to MaxGrowth, got %v", err)
+	}
+	if len(icc.Program) != MaxGrowth+1 || icc.Program[MaxGrowth] != 7 {
+		t.Errorf("Expected memory to grow to %d words, got %d", MaxGrowth+1, len(icc.Program))
+	}
+}
+
+func TestRemoteInputDroppedAfterHalt(t *testing.T) {
+	icc := NewIntCodeComputer([]int{99})
+	icc.DoneChannel = make(chan bool, 1)
+	c := serveDebug(t, icc)
+	go icc.Run()
+	if s, err := c.Wait(time.Second); err != nil || !s.Halted {
+		t.Fatalf("Expected to halt, got %+v, %v", s, err)
+	}
+
+	before := runtime.NumGoroutine()
+	if err := c.Input(1, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected input to be dropped after the program halted, got %d goroutines, had %d", after, before)
+	}
+}
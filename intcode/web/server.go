@@ -0,0 +1,378 @@
+// Package web serves a live view of a running IntCodeComputer to a browser on
+// localhost: registers, disassembly around IP, a memory write heatmap, the I/O
+// log and, for programs that draw, the grid they draw.
+package web
+
+import (
+	"adventofcode/intcode"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//go:embed static
+var static embed.FS
+
+// How program output is interpreted as a grid.
+const (
+	NoGrid      = iota
+	TileGrid    // (x, y, tile) triples, as day13's arcade. (-1, 0, n) is the score.
+	PainterGrid // (color, turn) pairs, as day11's hull painting robot.
+)
+
+const (
+	ioLogSize        = 200
+	disassemblyLines = 12
+	snapshotInterval = 100 * time.Millisecond
+)
+
+type IO struct {
+	Input bool `json:"input"`
+	Value int  `json:"value"`
+	Steps int  `json:"steps"`
+}
+
+type point struct {
+	X int
+	Y int
+}
+
+type Server struct {
+	GridMode int
+
+	icc *intcode.IntCodeComputer
+	dbg *intcode.Debugger
+
+	mu       sync.Mutex
+	memory   []int
+	taken    time.Time
+	writes   map[int]int
+	io       []IO
+	pending  []int
+	grid     map[point]int
+	score    int
+	robot    point
+	heading  int
+	painting bool
+}
+
+// New attaches a server to icc, using dbg for pause, step and resume. It must
+// be called before the program starts running.
+func New(icc *intcode.IntCodeComputer, dbg *intcode.Debugger, gridMode int) *Server {
+	s := &Server{
+		GridMode: gridMode,
+		icc:      icc,
+		dbg:      dbg,
+		writes:   make(map[int]int),
+		grid:     make(map[point]int),
+	}
+	icc.AddHook(s.hook)
+	return s
+}
+
+func (s *Server) hook(icc *intcode.IntCodeComputer, e intcode.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e.Kind {
+	case intcode.StepEvent, intcode.HaltEvent:
+		if e.Kind == intcode.HaltEvent || time.Since(s.taken) > snapshotInterval {
+			s.snapshot(icc)
+		}
+	case intcode.WriteEvent:
+		s.writes[e.Addr]++
+	case intcode.InputEvent:
+		s.log(IO{Input: true, Value: e.Value, Steps: icc.Steps})
+	case intcode.OutputEvent:
+		s.log(IO{Value: e.Value, Steps: icc.Steps})
+		s.draw(e.Value)
+	}
+}
+
+func (s *Server) snapshot(icc *intcode.IntCodeComputer) {
+	s.memory = append(s.memory[:0], icc.Program...)
+	s.taken = time.Now()
+}
+
+func (s *Server) log(entry IO) {
+	s.io = append(s.io, entry)
+	if len(s.io) > ioLogSize {
+		s.io = s.io[len(s.io)-ioLogSize:]
+	}
+}
+
+func (s *Server) draw(v int) {
+	switch s.GridMode {
+	case TileGrid:
+		s.pending = append(s.pending, v)
+		if len(s.pending) < 3 {
+			return
+		}
+		x, y, tile := s.pending[0], s.pending[1], s.pending[2]
+		s.pending = s.pending[:0]
+		if x == -1 && y == 0 {
+			s.score = tile
+		} else {
+			s.grid[point{x, y}] = tile
+		}
+	case PainterGrid:
+		if !s.painting {
+			s.grid[s.robot] = v
+			s.painting = true
+			return
+		}
+		s.painting = false
+		if v == 0 {
+			s.heading = (s.heading + 3) % 4
+		} else {
+			s.heading = (s.heading + 1) % 4
+		}
+		switch s.heading {
+		case 0:
+			s.robot.Y--
+		case 1:
+			s.robot.X++
+		case 2:
+			s.robot.Y++
+		case 3:
+			s.robot.X--
+		}
+	}
+}
+
+type line struct {
+	Addr int    `json:"addr"`
+	Text string `json:"text"`
+}
+
+type gridState struct {
+	Mode   int   `json:"mode"`
+	MinX   int   `json:"minX"`
+	MinY   int   `json:"minY"`
+	Width  int   `json:"width"`
+	Height int   `json:"height"`
+	Cells  []int `json:"cells"`
+	Score  int   `json:"score"`
+	Robot  point `json:"robot"`
+}
+
+type state struct {
+	intcode.State
+	Disassembly []line     `json:"disassembly"`
+	Heat        []int      `json:"heat"`
+	IO          []IO       `json:"io"`
+	Grid        *gridState `json:"grid,omitempty"`
+}
+
+func (s *Server) state() state {
+	// A stopped computer can be read directly, so the view is exact while
+	// single stepping.
+	s.dbg.Do(func(icc *intcode.IntCodeComputer) {
+		s.mu.Lock()
+		s.snapshot(icc)
+		s.mu.Unlock()
+	})
+
+	st := state{State: s.dbg.State()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st.Disassembly = around(s.memory, st.IP, disassemblyLines)
+
+	st.Heat = make([]int, len(s.memory))
+	for addr, n := range s.writes {
+		if addr < len(st.Heat) {
+			st.Heat[addr] = n
+		}
+	}
+
+	st.IO = append([]IO{}, s.io...)
+
+	if s.GridMode != NoGrid && len(s.grid) > 0 {
+		st.Grid = s.gridState()
+	}
+	return st
+}
+
+func (s *Server) gridState() *gridState {
+	first := true
+	var min, max point
+	for p := range s.grid {
+		if first || p.X < min.X {
+			min.X = p.X
+		}
+		if first || p.Y < min.Y {
+			min.Y = p.Y
+		}
+		if first || p.X > max.X {
+			max.X = p.X
+		}
+		if first || p.Y > max.Y {
+			max.Y = p.Y
+		}
+		first = false
+	}
+
+	g := &gridState{
+		Mode:   s.GridMode,
+		MinX:   min.X,
+		MinY:   min.Y,
+		Width:  max.X - min.X + 1,
+		Height: max.Y - min.Y + 1,
+		Score:  s.score,
+		Robot:  s.robot,
+	}
+	g.Cells = make([]int, g.Width*g.Height)
+	for p, v := range s.grid {
+		g.Cells[(p.Y-min.Y)*g.Width+p.X-min.X] = v
+	}
+	return g
+}
+
+// around disassembles n instructions either side of ip. The instructions
+// before ip come from a linear sweep from address 0; if ip is not on an
+// instruction boundary of that sweep, decoding restarts at ip.
+func around(memory []int, ip, n int) []line {
+	if ip >= len(memory) {
+		return nil
+	}
+
+	var before []intcode.Instruction
+	for addr := 0; addr < ip; {
+		in := intcode.Decode(memory, addr)
+		before = append(before, in)
+		addr += len(in.Words)
+	}
+	for len(before) > 0 && before[len(before)-1].Addr+len(before[len(before)-1].Words) > ip {
+		before = before[:len(before)-1]
+	}
+	if len(before) > n {
+		before = before[len(before)-n:]
+	}
+
+	var lines []line
+	for _, in := range before {
+		lines = append(lines, line{in.Addr, in.String()})
+	}
+	for addr := ip; addr < len(memory) && len(lines) < len(before)+n+1; {
+		in := intcode.Decode(memory, addr)
+		lines = append(lines, line{in.Addr, in.String()})
+		addr += len(in.Words)
+	}
+	return lines
+}
+
+// Handler returns the server's HTTP handler.
+func (s *Server) Handler() http.Handler {
+	assets, _ := fs.Sub(static, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(assets)))
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.state())
+	})
+	mux.HandleFunc("/events", s.events)
+	mux.HandleFunc("/pause", s.control(func(r *http.Request) { s.dbg.Pause() }))
+	mux.HandleFunc("/resume", s.control(func(r *http.Request) { s.dbg.Resume() }))
+	mux.HandleFunc("/step", s.control(func(r *http.Request) {
+		n, err := strconv.Atoi(r.FormValue("n"))
+		if err != nil || n < 1 {
+			n = 1
+		}
+		s.dbg.Step(n)
+		s.dbg.Wait(time.Second)
+	}))
+
+	return localOnly(mux)
+}
+
+func (s *Server) control(f func(r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		f(r)
+		writeJSON(w, s.state())
+	}
+}
+
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ticker := time.NewTicker(snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		data, err := json.Marshal(s.state())
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// localOnly rejects requests addressed to anything but a loopback host, so a
+// page on another site can't reach the server through DNS rebinding.
+func localOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if !isLoopback(host) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ListenAndServe serves on addr, which must be a loopback address such as
+// "localhost:8019".
+func (s *Server) ListenAndServe(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("web: %s is not a loopback address", addr)
+	}
+	return http.ListenAndServe(addr, s.Handler())
+}
@@ -0,0 +1,156 @@
+package web
+
+import (
+	"adventofcode/intcode"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalOnly(t *testing.T) {
+	specs := map[string]int{
+		"localhost:8019": http.StatusOK,
+		"localhost":      http.StatusOK,
+		"127.0.0.1:8019": http.StatusOK,
+		"[::1]:8019":     http.StatusOK,
+		"example.com":    http.StatusForbidden,
+		"evil.test:8019": http.StatusForbidden,
+		"192.168.1.2:80": http.StatusForbidden,
+		"localhost.test": http.StatusForbidden,
+	}
+
+	h := localOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for host, want := range specs {
+		t.Run(host, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = host
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != want {
+				t.Errorf("Expected %d, got %d", want, w.Code)
+			}
+		})
+	}
+}
+
+// serve starts a paused loop that never halts: out 7; jmp 0.
+func serve(t *testing.T) (*httptest.Server, *intcode.Debugger) {
+	t.Helper()
+	icc := intcode.NewIntCodeComputer([]int{104, 7, 1105, 1, 0})
+	icc.OutputChannel = make(chan int, 100)
+	go func() {
+		for range icc.OutputChannel {
+		}
+	}()
+	dbg := intcode.NewDebugger(icc)
+	s := New(icc, dbg, NoGrid)
+	dbg.Pause()
+	go icc.Run()
+	if _, ok := dbg.Wait(time.Second); !ok {
+		t.Fatal("Expected the computer to stop before its first instruction")
+	}
+
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(func() {
+		srv.Close()
+		dbg.Pause()
+	})
+	return srv, dbg
+}
+
+func post(t *testing.T, url string) state {
+	t.Helper()
+	resp, err := http.Post(url, "application/x-www-form-urlencoded", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 from %s, got %d", url, resp.StatusCode)
+	}
+	var st state
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestControl(t *testing.T) {
+	srv, dbg := serve(t)
+
+	st := post(t, srv.URL+"/step?n=3")
+	if !st.Stopped || st.Steps != 3 || st.IP != 2 {
+		t.Fatalf("Expected to stop at 2 after 3 steps, got %+v", st.State)
+	}
+	if len(st.IO) != 2 || st.IO[0].Value != 7 {
+		t.Errorf("Expected two outputs of 7, got %v", st.IO)
+	}
+
+	st = post(t, srv.URL+"/step")
+	if st.Steps != 4 || st.IP != 0 {
+		t.Errorf("Expected to stop at 0 after 4 steps, got %+v", st.State)
+	}
+
+	st = post(t, srv.URL+"/resume")
+	if st.Paused {
+		t.Errorf("Expected resume to unpause, got %+v", st.State)
+	}
+
+	post(t, srv.URL+"/pause")
+	if s, ok := dbg.Wait(time.Second); !ok || s.Steps <= 4 {
+		t.Errorf("Expected to run on and stop again after pausing, got %+v", s)
+	}
+
+	resp, err := http.Get(srv.URL + "/step")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET /step to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	srv, _ := serve(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected an event stream, got %q", ct)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<20)
+	for i := 0; i < 2; i++ {
+		var data string
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "data: ") {
+				data = strings.TrimPrefix(scanner.Text(), "data: ")
+				break
+			}
+		}
+		if data == "" {
+			t.Fatalf("Expected event %d, got %v", i, scanner.Err())
+		}
+
+		var st state
+		if err := json.Unmarshal([]byte(data), &st); err != nil {
+			t.Fatal(err)
+		}
+		if !st.Stopped || st.IP != 0 || len(st.Disassembly) != 2 || st.Disassembly[0].Text != "out 7" {
+			t.Errorf("Expected the stopped computer and its disassembly, got %+v", st)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+"use strict";
+
+const palettes = {
+  1: ["#0f0f23", "#cc3333", "#9966cc", "#00cc00", "#ffff66"],
+  2: ["#0f0f23", "#ffffff"],
+};
+
+function post(path) {
+  fetch(path, { method: "POST" }).then((r) => r.json()).then(render);
+}
+
+document.getElementById("pause").onclick = () => post("/pause");
+document.getElementById("step").onclick = () => post("/step");
+document.getElementById("resume").onclick = () => post("/resume");
+
+function render(s) {
+  let status = s.Halted ? "halted" : s.Stopped ? "stopped" : s.Paused ? "pausing" : "running";
+  document.getElementById("registers").textContent =
+    `IP=${s.IP} RB=${s.RelBase} steps=${s.Steps} ${status}`;
+
+  const dis = document.getElementById("disassembly");
+  dis.replaceChildren(...(s.disassembly || []).map((l) => {
+    const span = document.createElement("span");
+    span.textContent = `${String(l.addr).padStart(5, "0")}: ${l.text}\n`;
+    if (l.addr === s.IP) span.className = "current";
+    return span;
+  }));
+
+  drawHeat(s.heat || [], s.IP);
+
+  document.getElementById("io").textContent = (s.io || [])
+    .map((e) => `${String(e.steps).padStart(10)} ${e.input ? "in " : "out"} ${e.value}`)
+    .reverse()
+    .join("\n");
+
+  if (s.grid) drawGrid(s.grid);
+}
+
+function drawHeat(heat, ip) {
+  const canvas = document.getElementById("heat");
+  const cols = 64;
+  const rows = Math.max(1, Math.ceil(heat.length / cols));
+  const size = 6;
+  canvas.width = cols * size;
+  canvas.height = rows * size;
+
+  const ctx = canvas.getContext("2d");
+  const max = Math.log(1 + Math.max(1, ...heat));
+  heat.forEach((n, addr) => {
+    const level = Math.log(1 + n) / max;
+    ctx.fillStyle = addr === ip ? "#ffff66" : `rgb(${Math.round(255 * level)}, 32, ${Math.round(96 * (1 - level))})`;
+    ctx.fillRect((addr % cols) * size, Math.floor(addr / cols) * size, size, size);
+  });
+}
+
+function drawGrid(g) {
+  document.getElementById("grid-section").hidden = false;
+  document.getElementById("score").textContent = g.score ? `score ${g.score}` : "";
+
+  const canvas = document.getElementById("grid");
+  const size = Math.max(2, Math.floor(480 / Math.max(g.width, g.height)));
+  canvas.width = g.width * size;
+  canvas.height = g.height * size;
+
+  const ctx = canvas.getContext("2d");
+  g.cells.forEach((v, i) => {
+    ctx.fillStyle = palettes[g.mode][v] || "#ffffff";
+    ctx.fillRect((i % g.width) * size, Math.floor(i / g.width) * size, size, size);
+  });
+}
+
+new EventSource("/events").onmessage = (e) => render(JSON.parse(e.data));
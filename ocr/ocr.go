@@ -0,0 +1,116 @@
+// Package ocr reads the block capital letters some puzzles draw as their
+// answer.
+package ocr
+
+import (
+	"adventofcode/grid"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// font maps the drawing of each letter, as returned by glyphs, to the letter.
+type font map[string]rune
+
+var fonts = map[int]font{
+	6:  font4x6,
+	10: font6x10,
+}
+
+// Read returns the letters drawn in g by the cells equal to on. The letters
+// must be separated by at least one blank column.
+func Read[T comparable](g grid.Grid[T], on T) (string, error) {
+	lit := make(map[grid.Point]bool)
+	var points []grid.Point
+	b := g.Bounds()
+	for row := b.Min.Row; row <= b.Max.Row; row++ {
+		for col := b.Min.Col; col <= b.Max.Col; col++ {
+			p := grid.Point{Row: row, Col: col}
+			if v, ok := g.At(p); ok && v == on {
+				lit[p] = true
+				points = append(points, p)
+			}
+		}
+	}
+
+	bounds := grid.Bounds(points)
+	if bounds.Empty() {
+		return "", errors.New("ocr: nothing is drawn")
+	}
+	f, ok := fonts[bounds.Rows()]
+	if !ok {
+		return "", fmt.Errorf("ocr: no font is %d pixels tall", bounds.Rows())
+	}
+
+	letters := make([]rune, 0)
+	for i, glyph := range glyphs(lit, bounds) {
+		letter, ok := f[glyph]
+		if !ok {
+			return "", fmt.Errorf("ocr: unknown letter %d:\n%s", i+1, glyph)
+		}
+		letters = append(letters, letter)
+	}
+	return string(letters), nil
+}
+
+// glyphs splits the drawing inside bounds on blank columns and draws each
+// part with # and . rows.
+func glyphs(lit map[grid.Point]bool, bounds grid.Rect) []string {
+	blank := func(col int) bool {
+		for row := bounds.Min.Row; row <= bounds.Max.Row; row++ {
+			if lit[grid.Point{Row: row, Col: col}] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var drawn []string
+	for col := bounds.Min.Col; col <= bounds.Max.Col; col++ {
+		if blank(col) {
+			continue
+		}
+		start := col
+		for col <= bounds.Max.Col && !blank(col) {
+			col++
+		}
+
+		rows := make([]string, 0, bounds.Rows())
+		for row := bounds.Min.Row; row <= bounds.Max.Row; row++ {
+			var b strings.Builder
+			for c := start; c < col; c++ {
+				if lit[grid.Point{Row: row, Col: c}] {
+					b.WriteByte('#')
+				} else {
+					b.WriteByte('.')
+				}
+			}
+			rows = append(rows, b.String())
+		}
+		drawn = append(drawn, strings.Join(rows, "\n"))
+	}
+	return drawn
+}
+
+// newFont reads letters from a drawing of them side by side.
+func newFont(letters, drawing string) font {
+	lit := make(map[grid.Point]bool)
+	rows := strings.Split(strings.Trim(drawing, "\n"), "\n")
+	for row, line := range rows {
+		for col, c := range line {
+			if c == '#' {
+				lit[grid.Point{Row: row, Col: col}] = true
+			}
+		}
+	}
+
+	drawn := glyphs(lit, grid.Rect{Max: grid.Point{Row: len(rows) - 1, Col: len(rows[0]) - 1}})
+	if len(drawn) != len(letters) {
+		panic(fmt.Sprintf("ocr: drawing has %d letters, expected %d", len(drawn), len(letters)))
+	}
+	f := make(font)
+	for i, letter := range letters {
+		f[drawn[i]] = letter
+	}
+	return f
+}
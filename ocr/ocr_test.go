@@ -0,0 +1,95 @@
+package ocr
+
+import (
+	"adventofcode/grid"
+	"strings"
+	"testing"
+)
+
+// drawing makes a sparse grid from lines of # and spaces, shifted by offset.
+func drawing(lines string, offset grid.Point) grid.Sparse[rune] {
+	g := make(grid.Sparse[rune])
+	for row, line := range strings.Split(strings.Trim(lines, "\n"), "\n") {
+		for col, c := range line {
+			g[grid.Point{Row: row, Col: col}.Add(offset)] = c
+		}
+	}
+	return g
+}
+
+func TestRead(t *testing.T) {
+	specs := []struct {
+		lines    string
+		offset   grid.Point
+		expected string
+	}{
+		{`
+ ##  #### ###  #  # 
+#  # #    #  # #  # 
+#  # ###  ###  #  # 
+#### #    #  # #  # 
+#  # #    #  # #  # 
+#  # #### ###   ##  `, grid.Point{}, "AEBU"},
+		{`
+
+   ## #### ###  #### #   #
+    # #    #  # #    #   #
+    # ###  ###  ###   # # 
+    # #    #  # #      #  
+ #  # #    #  # #      #  
+  ##  #    ###  ####   #  
+`, grid.Point{Row: -3, Col: 7}, "JFBEY"},
+		{`
+#####   #    #  ######
+#    #  #    #       #
+#    #   #  #        #
+#    #   #  #       # 
+#####     ##       #  
+#  #      ##      #   
+#   #    #  #    #    
+#   #    #  #   #     
+#    #  #    #  #     
+#    #  #    #  ######`, grid.Point{}, "RXZ"},
+	}
+
+	for _, spec := range specs {
+		s, err := Read[rune](drawing(spec.lines, spec.offset), '#')
+		if err != nil {
+			t.Errorf("Expected %q, got error %v", spec.expected, err)
+			continue
+		}
+		if s != spec.expected {
+			t.Errorf("Expected %q, got %q", spec.expected, s)
+		}
+	}
+}
+
+func TestReadDense(t *testing.T) {
+	g := grid.FromRows([][]int{
+		{1, 0, 0, 1, 0, 1, 1, 1},
+		{1, 0, 0, 1, 0, 0, 1, 0},
+		{1, 1, 1, 1, 0, 0, 1, 0},
+		{1, 0, 0, 1, 0, 0, 1, 0},
+		{1, 0, 0, 1, 0, 0, 1, 0},
+		{1, 0, 0, 1, 0, 1, 1, 1},
+	})
+	if s, err := Read[int](g, 1); err != nil || s != "HI" {
+		t.Errorf("Expected \"HI\", got %q, %v", s, err)
+	}
+	if _, err := Read[int](g, 0); err == nil {
+		t.Error("Expected an error reading the unlit cells")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	specs := []string{
+		"",
+		"#\n#\n#",
+		"####\n#  #\n#  #\n#  #\n#  #\n####",
+	}
+	for _, lines := range specs {
+		if s, err := Read[rune](drawing(lines, grid.Point{}), '#'); err == nil {
+			t.Errorf("Expected an error for\n%s\ngot %q", lines, s)
+		}
+	}
+}
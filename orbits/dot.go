@@ -0,0 +1,37 @@
+package orbits
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the map as a Graphviz graph with an edge from each body to
+// the bodies orbiting it. Bodies in highlight are filled in.
+func (m *Map) WriteDOT(w io.Writer, highlight ...string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph orbits {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	for _, name := range highlight {
+		if _, ok := m.index[name]; ok {
+			fmt.Fprintf(bw, "\t%s [style=filled, fillcolor=gold];\n", quote(name))
+		}
+	}
+	for _, v := range m.order {
+		for _, c := range m.children[v] {
+			fmt.Fprintf(bw, "\t%s -> %s;\n", quote(m.names[v]), quote(m.names[c]))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotEscaper escapes the characters that would end a DOT quoted ID early.
+// Unlike Go, DOT takes every other character literally.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quote returns name as a DOT quoted ID.
+func quote(name string) string {
+	return `"` + dotEscaper.Replace(name) + `"`
+}
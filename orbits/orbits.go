@@ -0,0 +1,269 @@
+// Package orbits models a map of bodies orbiting each other as a tree, with
+// distance and common ancestor queries between any two bodies.
+package orbits
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const none = -1
+
+// Builder collects orbits until they are checked and turned into a Map.
+type Builder struct {
+	names  []string
+	index  map[string]int
+	parent []int
+}
+
+func NewBuilder() *Builder {
+	return &Builder{index: make(map[string]int)}
+}
+
+func (b *Builder) body(name string) int {
+	if i, ok := b.index[name]; ok {
+		return i
+	}
+	i := len(b.names)
+	b.index[name] = i
+	b.names = append(b.names, name)
+	b.parent = append(b.parent, none)
+	return i
+}
+
+// Add records that orbiter directly orbits center. A body may only orbit one
+// other.
+func (b *Builder) Add(center, orbiter string) error {
+	if center == "" || orbiter == "" {
+		return fmt.Errorf("orbits: empty body name in %s)%s", center, orbiter)
+	}
+	if center == orbiter {
+		return fmt.Errorf("orbits: %s orbits itself", center)
+	}
+	c, o := b.body(center), b.body(orbiter)
+	if p := b.parent[o]; p != none && p != c {
+		return fmt.Errorf("orbits: %s orbits both %s and %s", orbiter, b.names[p], center)
+	}
+	b.parent[o] = c
+	return nil
+}
+
+// Map checks that the orbits form a single tree, with one body at the root
+// and no cycles, and returns it.
+func (b *Builder) Map() (*Map, error) {
+	if len(b.names) == 0 {
+		return nil, fmt.Errorf("orbits: no orbits")
+	}
+
+	m := &Map{
+		names:    b.names,
+		index:    b.index,
+		parent:   b.parent,
+		children: make([][]int, len(b.names)),
+		depth:    make([]int, len(b.names)),
+		size:     make([]int, len(b.names)),
+		jump:     make([]int, len(b.names)),
+		root:     none,
+	}
+
+	var roots []string
+	for i, p := range m.parent {
+		if p == none {
+			roots = append(roots, m.names[i])
+			m.root = i
+		} else {
+			m.children[p] = append(m.children[p], i)
+		}
+	}
+	if len(roots) > 1 {
+		sort.Strings(roots)
+		return nil, fmt.Errorf("orbits: more than one body orbits nothing: %s", strings.Join(roots, ", "))
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("orbits: cycle through %s", m.cycle(0))
+	}
+
+	// Visit parents before children to fill in depths and jump pointers,
+	// without recursing so chains of any depth fit on the stack.
+	m.order = append(make([]int, 0, len(m.names)), m.root)
+	m.jump[m.root] = m.root
+	for i := 0; i < len(m.order); i++ {
+		v := m.order[i]
+		m.total += m.depth[v]
+		for _, c := range m.children[v] {
+			m.depth[c] = m.depth[v] + 1
+			m.jump[c] = m.jumpFrom(v)
+			m.order = append(m.order, c)
+		}
+	}
+	if len(m.order) < len(m.names) {
+		visited := make([]bool, len(m.names))
+		for _, v := range m.order {
+			visited[v] = true
+		}
+		for v := range m.names {
+			if !visited[v] {
+				return nil, fmt.Errorf("orbits: cycle through %s", m.cycle(v))
+			}
+		}
+	}
+
+	// Children come after their parents, so going backwards every body is
+	// sized before its parent.
+	for i := len(m.order) - 1; i >= 0; i-- {
+		v := m.order[i]
+		m.size[v]++
+		if v != m.root {
+			m.size[m.parent[v]] += m.size[v]
+		}
+	}
+	return m, nil
+}
+
+// Map is a tree of orbits around a single root body.
+type Map struct {
+	names    []string
+	index    map[string]int
+	parent   []int
+	children [][]int
+	depth    []int
+	// size is the number of bodies in the subtree of each body, itself
+	// included.
+	size  []int
+	total int
+	// jump is an ancestor further up than the parent, spaced as in a skew
+	// binary number so that any ancestor is reachable in O(log n) jumps.
+	jump  []int
+	order []int
+	root  int
+}
+
+// jumpFrom returns the jump pointer of a child of p.
+func (m *Map) jumpFrom(p int) int {
+	j := m.jump[p]
+	if m.depth[p]-m.depth[j] == m.depth[j]-m.depth[m.jump[j]] {
+		return m.jump[j]
+	}
+	return p
+}
+
+// cycle names the bodies of the cycle reached by following orbits from v.
+func (m *Map) cycle(v int) string {
+	seen := make(map[int]bool)
+	for !seen[v] {
+		seen[v] = true
+		v = m.parent[v]
+	}
+	names := []string{m.names[v]}
+	for u := m.parent[v]; u != v; u = m.parent[u] {
+		names = append(names, m.names[u])
+	}
+	return strings.Join(names, ", ")
+}
+
+func (m *Map) lookup(name string) (int, error) {
+	if i, ok := m.index[name]; ok {
+		return i, nil
+	}
+	return none, fmt.Errorf("orbits: unknown body %s", name)
+}
+
+// Root is the body everything else orbits, directly or indirectly.
+func (m *Map) Root() string {
+	return m.names[m.root]
+}
+
+// Len is the number of bodies.
+func (m *Map) Len() int {
+	return len(m.names)
+}
+
+// Parent returns the body name directly orbits.
+func (m *Map) Parent(name string) (string, error) {
+	v, err := m.lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if v == m.root {
+		return "", fmt.Errorf("orbits: %s orbits nothing", name)
+	}
+	return m.names[m.parent[v]], nil
+}
+
+// Depth is the number of bodies name orbits, directly or indirectly.
+func (m *Map) Depth(name string) (int, error) {
+	v, err := m.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	return m.depth[v], nil
+}
+
+// TotalOrbits is the number of direct and indirect orbits in the map.
+func (m *Map) TotalOrbits() int {
+	return m.total
+}
+
+// Orbiters is the number of bodies orbiting name, directly or indirectly.
+func (m *Map) Orbiters(name string) (int, error) {
+	v, err := m.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	return m.size[v] - 1, nil
+}
+
+// ancestor returns the ancestor of v at depth d, which is at most v's.
+func (m *Map) ancestor(v, d int) int {
+	for m.depth[v] > d {
+		if m.depth[m.jump[v]] >= d {
+			v = m.jump[v]
+		} else {
+			v = m.parent[v]
+		}
+	}
+	return v
+}
+
+func (m *Map) lca(a, b int) int {
+	if m.depth[a] > m.depth[b] {
+		a = m.ancestor(a, m.depth[b])
+	} else {
+		b = m.ancestor(b, m.depth[a])
+	}
+	for a != b {
+		if m.jump[a] != m.jump[b] {
+			a, b = m.jump[a], m.jump[b]
+		} else {
+			a, b = m.parent[a], m.parent[b]
+		}
+	}
+	return a
+}
+
+// CommonAncestor returns the deepest body that both a and b are, or orbit.
+func (m *Map) CommonAncestor(a, b string) (string, error) {
+	va, err := m.lookup(a)
+	if err != nil {
+		return "", err
+	}
+	vb, err := m.lookup(b)
+	if err != nil {
+		return "", err
+	}
+	return m.names[m.lca(va, vb)], nil
+}
+
+// Distance is the number of orbital transfers between a and b.
+func (m *Map) Distance(a, b string) (int, error) {
+	va, err := m.lookup(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := m.lookup(b)
+	if err != nil {
+		return 0, err
+	}
+	return m.depth[va] + m.depth[vb] - 2*m.depth[m.lca(va, vb)], nil
+}
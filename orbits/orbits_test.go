@@ -0,0 +1,209 @@
+package orbits
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+const example = `COM)B
+B)C
+C)D
+D)E
+E)F
+B)G
+G)H
+D)I
+E)J
+J)K
+K)L
+K)YOU
+I)SAN
+`
+
+func mustParse(t *testing.T, input string) *Map {
+	t.Helper()
+	m, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestExample(t *testing.T) {
+	m := mustParse(t, example)
+	if m.Root() != "COM" || m.Len() != 14 {
+		t.Errorf("Expected 14 bodies around COM, got %d around %s", m.Len(), m.Root())
+	}
+	if total := m.TotalOrbits() - 7 - 5; total != 42 {
+		t.Errorf("Expected 42 orbits without YOU and SAN, got %d", total)
+	}
+
+	specs := []struct {
+		a, b     string
+		ancestor string
+		distance int
+	}{
+		{"YOU", "SAN", "D", 6},
+		{"K", "I", "D", 4},
+		{"L", "L", "L", 0},
+		{"COM", "L", "COM", 7},
+		{"H", "F", "B", 6},
+		{"J", "L", "J", 2},
+	}
+	for _, spec := range specs {
+		ancestor, err := m.CommonAncestor(spec.a, spec.b)
+		if err != nil || ancestor != spec.ancestor {
+			t.Errorf("%s and %s: expected common ancestor %s, got %s (%v)", spec.a, spec.b, spec.ancestor, ancestor, err)
+		}
+		distance, err := m.Distance(spec.a, spec.b)
+		if err != nil || distance != spec.distance {
+			t.Errorf("%s and %s: expected distance %d, got %d (%v)", spec.a, spec.b, spec.distance, distance, err)
+		}
+	}
+
+	for name, expected := range map[string]int{"COM": 13, "D": 8, "K": 2, "SAN": 0} {
+		if n, err := m.Orbiters(name); err != nil || n != expected {
+			t.Errorf("Expected %d bodies orbiting %s, got %d (%v)", expected, name, n, err)
+		}
+	}
+
+	if p, err := m.Parent("YOU"); err != nil || p != "K" {
+		t.Errorf("Expected YOU to orbit K, got %s (%v)", p, err)
+	}
+	if _, err := m.Parent("COM"); err == nil {
+		t.Error("Expected an error for the parent of COM")
+	}
+	if _, err := m.Distance("YOU", "Pluto"); err == nil {
+		t.Error("Expected an error for an unknown body")
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	specs := []struct {
+		input    string
+		expected string
+	}{
+		{"", "no orbits"},
+		{"COM)B\nB", "line 2: invalid orbit"},
+		{"COM)B\n\n  \nB)", "line 4: orbits: empty body name"},
+		{"COM)B\nB)C)D", "line 2: invalid orbit"},
+		{"COM)B\nCOM)C\nB)D\nC)D", "line 4: orbits: D orbits both B and C"},
+		{"A)A", "A orbits itself"},
+		{"COM)B\nX)Y\nQ)Z", "more than one body orbits nothing: COM, Q, X"},
+		{"A)B\nB)C\nC)A", "cycle through"},
+		{"COM)B\nB)C\nD)E\nE)F\nF)D", "cycle through"},
+	}
+	for _, spec := range specs {
+		_, err := Parse(spec.input)
+		if err == nil || !strings.Contains(err.Error(), spec.expected) {
+			t.Errorf("%q: expected an error containing %q, got %v", spec.input, spec.expected, err)
+		}
+	}
+
+	// Listing the same orbit twice is fine.
+	mustParse(t, "COM)B\nCOM)B")
+}
+
+func TestCommonAncestorRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	b := NewBuilder()
+	parents := map[string]string{}
+	for i := 1; i < 2000; i++ {
+		// Mostly extend recent bodies to make deep chains.
+		p := i - 1 - r.Intn(min(i, 5))
+		if r.Intn(10) == 0 {
+			p = r.Intn(i)
+		}
+		name, parent := fmt.Sprint(i), fmt.Sprint(p)
+		parents[name] = parent
+		if err := b.Add(parent, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := b.Map()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ancestors := func(name string) []string {
+		path := []string{name}
+		for name != "0" {
+			name = parents[name]
+			path = append(path, name)
+		}
+		return path
+	}
+	for i := 0; i < 500; i++ {
+		a, c := fmt.Sprint(r.Intn(2000)), fmt.Sprint(r.Intn(2000))
+		onA := map[string]bool{}
+		for _, n := range ancestors(a) {
+			onA[n] = true
+		}
+		var expected string
+		for _, n := range ancestors(c) {
+			if onA[n] {
+				expected = n
+				break
+			}
+		}
+		if got, _ := m.CommonAncestor(a, c); got != expected {
+			t.Errorf("%s and %s: expected %s, got %s", a, c, expected, got)
+		}
+	}
+}
+
+func TestDeepChain(t *testing.T) {
+	const n = 500000
+	var b bytes.Buffer
+	b.WriteString("COM)0\n")
+	for i := 1; i < n; i++ {
+		fmt.Fprintf(&b, "%d)%d\n", i-1, i)
+	}
+	m, err := Load(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total := m.TotalOrbits(); total != n*(n+1)/2 {
+		t.Errorf("Expected %d orbits, got %d", n*(n+1)/2, total)
+	}
+	if orbiters, _ := m.Orbiters("COM"); orbiters != n {
+		t.Errorf("Expected %d bodies orbiting COM, got %d", n, orbiters)
+	}
+	if d, _ := m.Distance("12345", fmt.Sprint(n-1)); d != n-1-12345 {
+		t.Errorf("Expected distance %d, got %d", n-1-12345, d)
+	}
+	if a, _ := m.CommonAncestor("400000", "399999"); a != "399999" {
+		t.Errorf("Expected 399999, got %s", a)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	m := mustParse(t, "COM)B\nB)C\nCOM)\"D\"\nB)é\\E")
+	var b bytes.Buffer
+	if err := m.WriteDOT(&b, "C", "nowhere"); err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph orbits {
+	rankdir=LR;
+	"C" [style=filled, fillcolor=gold];
+	"COM" -> "B";
+	"COM" -> "\"D\"";
+	"B" -> "C";
+	"B" -> "é\\E";
+}
+`
+	if b.String() != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, b.String())
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
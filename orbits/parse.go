@@ -0,0 +1,36 @@
+package orbits
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Parse reads a map of one orbit per line, written as CENTER)ORBITER.
+func Parse(input string) (*Map, error) {
+	return Load(strings.NewReader(input))
+}
+
+// Load reads a map like Parse, a line at a time.
+func Load(r io.Reader) (*Map, error) {
+	b := NewBuilder()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		i := strings.IndexByte(text, ')')
+		if i < 0 || strings.IndexByte(text[i+1:], ')') >= 0 {
+			return nil, fmt.Errorf("line %d: invalid orbit %q", line, text)
+		}
+		if err := b.Add(text[:i], text[i+1:]); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b.Map()
+}
@@ -0,0 +1,88 @@
+// Command password counts, lists and explains the numbers in a range that
+// follow a set of password rules.
+package main
+
+import (
+	"adventofcode/password"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+const usage = `usage: password <command> [--rules rules] [arguments]
+
+commands:
+  count <from> <to>       count the matches in a range
+  list [--limit n] <from> <to>
+                          print the matches in a range in order
+  explain <n>...          print the first rule each number fails
+
+rules are separated by spaces, e.g. "len=6 nondecreasing run>=2 exactrun=2":
+  len=N nondecreasing run>=N exactrun=N maxrun=N
+`
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "count" && os.Args[1] != "list" && os.Args[1] != "explain") {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := run(os.Args[1], os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "password %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func run(command string, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	rulesFlag := fs.String("rules", "len=6 nondecreasing run>=2", "the rules every password follows")
+	limit := fs.Int("limit", 0, "stop listing after this many matches (0 for all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	rules, err := password.Parse(*rulesFlag)
+	if err != nil {
+		return err
+	}
+
+	numbers := make([]int, fs.NArg())
+	for i, arg := range fs.Args() {
+		if numbers[i], err = strconv.Atoi(arg); err != nil {
+			return fmt.Errorf("invalid number %q", arg)
+		}
+	}
+
+	switch command {
+	case "count":
+		if len(numbers) != 2 {
+			return errors.New("expected <from> <to>")
+		}
+		fmt.Fprintln(out, rules.Count(numbers[0], numbers[1]))
+	case "list":
+		if len(numbers) != 2 {
+			return errors.New("expected <from> <to>")
+		}
+		it := rules.Iter(numbers[0], numbers[1])
+		for i := 0; *limit == 0 || i < *limit; i++ {
+			n, ok := it.Next()
+			if !ok {
+				break
+			}
+			fmt.Fprintln(out, n)
+		}
+	case "explain":
+		if len(numbers) == 0 {
+			return errors.New("expected at least one number")
+		}
+		for _, n := range numbers {
+			if failed := rules.Explain(n); failed != nil {
+				fmt.Fprintf(out, "%d: fails %s\n", n, failed)
+			} else {
+				fmt.Fprintf(out, "%d: ok\n", n)
+			}
+		}
+	}
+	return nil
+}
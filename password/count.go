@@ -0,0 +1,175 @@
+package password
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// MaxDigits is the longest number Rules can count. Counting every number of
+// 19 digits would overflow an int.
+const MaxDigits = 18
+
+// maxNumber is the largest number of MaxDigits digits.
+const maxNumber = 999999999999999999
+
+// Rules is a set of rules a number must follow all of.
+type Rules []Rule
+
+func (rs Rules) start() []State {
+	states := make([]State, len(rs))
+	for i, r := range rs {
+		states[i] = r.Start()
+	}
+	return states
+}
+
+func (rs Rules) next(states []State, d int) ([]State, bool) {
+	next := make([]State, len(rs))
+	for i, r := range rs {
+		s, ok := r.Next(states[i], d)
+		if !ok {
+			return nil, false
+		}
+		next[i] = s
+	}
+	return next, true
+}
+
+func (rs Rules) accept(states []State) bool {
+	for i, r := range rs {
+		if !r.Accept(states[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether n follows every rule.
+func (rs Rules) Match(n int) bool {
+	if n < 0 {
+		return false
+	}
+	states := rs.start()
+	for _, c := range strconv.Itoa(n) {
+		var ok bool
+		if states, ok = rs.next(states, int(c-'0')); !ok {
+			return false
+		}
+	}
+	return rs.accept(states)
+}
+
+// Count returns how many numbers from from to to, both included, follow
+// every rule. Numbers of more than MaxDigits digits are not counted.
+func (rs Rules) Count(from, to int) int {
+	if to > maxNumber {
+		to = maxNumber
+	}
+	if to < from {
+		return 0
+	}
+	c := counter{rules: rs, memo: make(map[string]int)}
+	return c.upTo(to) - c.upTo(from-1)
+}
+
+// counter counts matches digit by digit, remembering how many ways there are
+// to finish a number from each combination of rule states.
+type counter struct {
+	rules Rules
+	memo  map[string]int
+}
+
+// upTo counts the matches from 0 to n.
+func (c *counter) upTo(n int) int {
+	if n < 0 {
+		return 0
+	}
+	limit := digits(n)
+	total := 0
+	for length := 1; length <= len(limit); length++ {
+		first := 1
+		if length == 1 {
+			first = 0
+		}
+		if length < len(limit) {
+			for d := first; d <= 9; d++ {
+				total += c.from(c.rules.start(), d, nil, length-1)
+			}
+			continue
+		}
+		for d := first; d <= limit[0]; d++ {
+			if d == limit[0] {
+				total += c.from(c.rules.start(), d, limit[1:], 0)
+			} else {
+				total += c.from(c.rules.start(), d, nil, length-1)
+			}
+		}
+	}
+	return total
+}
+
+// from counts the matches starting with digit d after states. When limit is
+// set the rest of the number may not exceed it; otherwise any free digits
+// may follow.
+func (c *counter) from(states []State, d int, limit []int, free int) int {
+	states, ok := c.rules.next(states, d)
+	if !ok {
+		return 0
+	}
+	if limit != nil {
+		return c.tight(states, limit)
+	}
+	return c.free(states, free)
+}
+
+func (c *counter) tight(states []State, limit []int) int {
+	if len(limit) == 0 {
+		if c.rules.accept(states) {
+			return 1
+		}
+		return 0
+	}
+	n := 0
+	for d := 0; d < limit[0]; d++ {
+		n += c.from(states, d, nil, len(limit)-1)
+	}
+	return n + c.from(states, limit[0], limit[1:], 0)
+}
+
+func (c *counter) free(states []State, remaining int) int {
+	if remaining == 0 {
+		if c.rules.accept(states) {
+			return 1
+		}
+		return 0
+	}
+
+	key := memoKey(states, remaining)
+	if n, ok := c.memo[key]; ok {
+		return n
+	}
+	n := 0
+	for d := 0; d <= 9; d++ {
+		n += c.from(states, d, nil, remaining-1)
+	}
+	c.memo[key] = n
+	return n
+}
+
+func memoKey(states []State, remaining int) string {
+	b := make([]byte, 1+8*len(states))
+	b[0] = byte(remaining)
+	for i, s := range states {
+		binary.LittleEndian.PutUint64(b[1+8*i:], uint64(s))
+	}
+	return string(b)
+}
+
+func digits(n int) []int {
+	s := strconv.Itoa(n)
+	ds := make([]int, len(s))
+	for i, c := range s {
+		ds[i] = int(c - '0')
+	}
+	return ds
+}
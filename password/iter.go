@@ -0,0 +1,78 @@
+package password
+
+// Iterator walks the matches in a range in ascending order.
+type Iterator struct {
+	c    counter
+	next int
+	to   int
+}
+
+// Iter returns an iterator over the numbers from from to to, both included,
+// that follow every rule. It stops after the numbers of MaxDigits digits.
+func (rs Rules) Iter(from, to int) *Iterator {
+	if from < 0 {
+		from = 0
+	}
+	if to > maxNumber {
+		to = maxNumber
+	}
+	return &Iterator{c: counter{rules: rs, memo: make(map[string]int)}, next: from, to: to}
+}
+
+// Next returns the next match, or false once there are no more.
+func (it *Iterator) Next() (int, bool) {
+	if it.next > it.to {
+		return 0, false
+	}
+	n, ok := it.c.atLeast(it.next)
+	if !ok || n > it.to {
+		it.next = it.to + 1
+		return 0, false
+	}
+	it.next = n + 1
+	return n, true
+}
+
+// atLeast returns the smallest match that is at least n.
+func (c *counter) atLeast(n int) (int, bool) {
+	low := digits(n)
+	for length := len(low); length <= MaxDigits; length++ {
+		if length > len(low) {
+			// Every number of this length is at least n.
+			low = make([]int, length)
+			low[0] = 1
+		}
+		if m, ok := c.first(c.rules.start(), low, true, 0); ok {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// first returns the smallest match made of prefix followed by len(low)
+// digits. While tight those digits may not be below low; otherwise anything
+// goes. Digits the rules reject, and prefixes no digits can finish, are
+// skipped without trying the numbers that start with them.
+func (c *counter) first(states []State, low []int, tight bool, prefix int) (int, bool) {
+	if len(low) == 0 {
+		return prefix, c.rules.accept(states)
+	}
+	start := 0
+	if tight {
+		start = low[0]
+	}
+	for d := start; d <= 9; d++ {
+		next, ok := c.rules.next(states, d)
+		if !ok {
+			continue
+		}
+		stillTight := tight && d == start
+		if !stillTight && c.free(next, len(low)-1) == 0 {
+			continue
+		}
+		if m, ok := c.first(next, low[1:], stillTight, prefix*10+d); ok {
+			return m, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,69 @@
+package password
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse reads rules separated by spaces:
+//
+//	len=N          exactly N digits
+//	nondecreasing  digits never decrease from left to right
+//	run>=N         some digit repeated at least N times in a row
+//	exactrun=N     some digit repeated exactly N times in a row
+//	maxrun=N       no digit repeated more than N times in a row
+func Parse(s string) (Rules, error) {
+	var rs Rules
+	for _, field := range strings.Fields(s) {
+		if field == "nondecreasing" {
+			rs = append(rs, NonDecreasing())
+			continue
+		}
+
+		var name, value string
+		if i := strings.Index(field, ">="); i > 0 {
+			name, value = field[:i+2], field[i+2:]
+		} else if i := strings.Index(field, "="); i > 0 {
+			name, value = field[:i+1], field[i+1:]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("password: invalid rule %q", field)
+		}
+
+		switch name {
+		case "len=":
+			rs = append(rs, Length(n))
+		case "run>=":
+			rs = append(rs, MinRun(n))
+		case "exactrun=":
+			rs = append(rs, ExactRun(n))
+		case "maxrun=":
+			rs = append(rs, MaxRun(n))
+		default:
+			return nil, fmt.Errorf("password: unknown rule %q", field)
+		}
+	}
+	return rs, nil
+}
+
+// String writes the rules as Parse reads them.
+func (rs Rules) String() string {
+	names := make([]string, len(rs))
+	for i, r := range rs {
+		names[i] = r.String()
+	}
+	return strings.Join(names, " ")
+}
+
+// Explain returns the first rule n does not follow, or nil if it follows
+// them all.
+func (rs Rules) Explain(n int) Rule {
+	for _, r := range rs {
+		if !(Rules{r}).Match(n) {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package password
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var (
+	part1Rules = Rules{Length(6), NonDecreasing(), Repeated()}
+	part2Rules = Rules{Length(6), NonDecreasing(), IsolatedPair()}
+)
+
+func TestMatch(t *testing.T) {
+	specs := []struct {
+		rules    Rules
+		n        int
+		expected bool
+	}{
+		{part1Rules, 111111, true},
+		{part1Rules, 223450, false},
+		{part1Rules, 123789, false},
+		{part1Rules, 11111, false},
+		{part2Rules, 112233, true},
+		{part2Rules, 123444, false},
+		{part2Rules, 111122, true},
+		{Rules{AllRuns("even", func(r Run) bool { return r.Length%2 == 0 })}, 11223333, true},
+		{Rules{AllRuns("even", func(r Run) bool { return r.Length%2 == 0 })}, 1122333, false},
+		{Rules{AnyRun("777", func(r Run) bool { return r.Digit == 7 && r.Length == 3 })}, 12777, true},
+		{Rules{NonDecreasing()}, -5, false},
+	}
+	for _, spec := range specs {
+		if m := spec.rules.Match(spec.n); m != spec.expected {
+			t.Errorf("%d: expected %t, got %t", spec.n, spec.expected, m)
+		}
+	}
+}
+
+func bruteForce(rs Rules, from, to int) int {
+	n := 0
+	for i := from; i <= to; i++ {
+		if rs.Match(i) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCountMatchesBruteForce(t *testing.T) {
+	ruleSets := []Rules{
+		part1Rules,
+		part2Rules,
+		{NonDecreasing()},
+		{Repeated()},
+		{AllRuns("nopairs", func(r Run) bool { return r.Length != 2 }), NonDecreasing()},
+		{AnyRun("zero", func(r Run) bool { return r.Digit == 0 })},
+		{},
+	}
+	ranges := [][2]int{{0, 0}, {0, 9}, {5, 120}, {99, 1000}, {136818, 685979}, {10, 5}}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		from := r.Intn(1000000)
+		ranges = append(ranges, [2]int{from, from + r.Intn(20000)})
+	}
+
+	for i, rs := range ruleSets {
+		for _, rg := range ranges {
+			expected := bruteForce(rs, rg[0], rg[1])
+			if n := rs.Count(rg[0], rg[1]); n != expected {
+				t.Errorf("Rules %d from %d to %d: expected %d, got %d", i, rg[0], rg[1], expected, n)
+			}
+		}
+	}
+}
+
+func TestCountLargeRange(t *testing.T) {
+	// Non-decreasing numbers of up to 18 digits are multisets of digits:
+	// C(27, 9) - 1 of them, plus 0.
+	rs := Rules{NonDecreasing()}
+	if n := rs.Count(0, 999999999999999999); n != 4686825 {
+		t.Errorf("Expected 4686825, got %d", n)
+	}
+
+	rs = Rules{NonDecreasing(), IsolatedPair()}
+	if n := rs.Count(123456789012345678, 999999999999999999); n <= 0 {
+		t.Errorf("Expected matches, got %d", n)
+	}
+}
+
+func TestMaxDigits(t *testing.T) {
+	rs := Rules{NonDecreasing()}
+	if n := rs.Count(0, math.MaxInt); n != 4686825 {
+		t.Errorf("Expected only numbers of up to %d digits to be counted, got %d", MaxDigits, n)
+	}
+	if n := rs.Count(1000000000000000000, math.MaxInt); n != 0 {
+		t.Errorf("Expected no numbers of %d digits to be counted, got %d", MaxDigits+1, n)
+	}
+
+	it := rs.Iter(999999999999999999, math.MaxInt)
+	if n, ok := it.Next(); !ok || n != 999999999999999999 {
+		t.Errorf("Expected 999999999999999999, got %d", n)
+	}
+	if n, ok := it.Next(); ok {
+		t.Errorf("Expected no more matches, got %d", n)
+	}
+	if n, ok := it.Next(); ok {
+		t.Errorf("Expected the iterator to stay done, got %d", n)
+	}
+}
+
+func TestParse(t *testing.T) {
+	specs := []struct {
+		s        string
+		expected string
+	}{
+		{"len=6 nondecreasing run>=2", "len=6 nondecreasing run>=2"},
+		{"  len=6\tnondecreasing exactrun=2 maxrun=3 ", "len=6 nondecreasing exactrun=2 maxrun=3"},
+		{"", ""},
+	}
+	for _, spec := range specs {
+		rs, err := Parse(spec.s)
+		if err != nil {
+			t.Errorf("%q: %v", spec.s, err)
+			continue
+		}
+		if rs.String() != spec.expected {
+			t.Errorf("%q: expected %q, got %q", spec.s, spec.expected, rs.String())
+		}
+	}
+
+	for _, s := range []string{"len", "len=", "len=x", "run>=-1", "increasing", "run=2", "=3"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Expected an error parsing %q", s)
+		}
+	}
+
+	rs, _ := Parse("len=6 nondecreasing exactrun=2")
+	if n, expected := rs.Count(100000, 999999), part2Rules.Count(100000, 999999); n != expected {
+		t.Errorf("Expected parsed rules to count %d, got %d", expected, n)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	specs := []struct {
+		n        int
+		expected string
+	}{
+		{112233, ""},
+		{223450, "nondecreasing"},
+		{123789, "exactrun=2"},
+		{123444, "exactrun=2"},
+		{11223, "len=6"},
+	}
+	for _, spec := range specs {
+		failed := part2Rules.Explain(spec.n)
+		if spec.expected == "" {
+			if failed != nil {
+				t.Errorf("%d: expected no failed rule, got %s", spec.n, failed)
+			}
+			continue
+		}
+		if failed == nil || failed.String() != spec.expected {
+			t.Errorf("%d: expected %s to fail, got %v", spec.n, spec.expected, failed)
+		}
+	}
+}
+
+func TestIter(t *testing.T) {
+	ruleSets := []Rules{
+		part1Rules,
+		part2Rules,
+		{NonDecreasing(), MaxRun(1)},
+		{MinRun(3)},
+		{},
+	}
+	ranges := [][2]int{{0, 0}, {0, 120}, {95, 1234}, {136818, 685979}, {-3, 15}, {10, 5}}
+
+	for i, rs := range ruleSets {
+		for _, rg := range ranges {
+			var expected []int
+			for n := rg[0]; n <= rg[1]; n++ {
+				if rs.Match(n) {
+					expected = append(expected, n)
+				}
+			}
+
+			var got []int
+			it := rs.Iter(rg[0], rg[1])
+			for n, ok := it.Next(); ok; n, ok = it.Next() {
+				got = append(got, n)
+			}
+			if len(got) != len(expected) {
+				t.Errorf("Rules %d from %d to %d: expected %d matches, got %d", i, rg[0], rg[1], len(expected), len(got))
+				continue
+			}
+			for j := range got {
+				if got[j] != expected[j] {
+					t.Errorf("Rules %d from %d to %d: match %d expected %d, got %d", i, rg[0], rg[1], j, expected[j], got[j])
+					break
+				}
+			}
+		}
+	}
+
+	// The next non-decreasing number after 136818 is 136888.
+	it := part1Rules.Iter(136818, 999999999)
+	if n, ok := it.Next(); !ok || n != 136888 {
+		t.Errorf("Expected 136888, got %d", n)
+	}
+
+	it = Rules{NonDecreasing()}.Iter(123456789012345678, 999999999999999999)
+	if n, ok := it.Next(); !ok || n != 123456789999999999 {
+		t.Errorf("Expected 123456789999999999, got %d", n)
+	}
+	it = Rules{NonDecreasing(), ExactRun(2)}.Iter(987654321987654321, 999999999999999999)
+	if n, ok := it.Next(); ok {
+		t.Errorf("Expected no more matches, got %d", n)
+	}
+}
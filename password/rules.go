@@ -0,0 +1,167 @@
+// Package password counts the numbers in a range whose digits follow a set
+// of rules, without checking every number.
+package password
+
+import "fmt"
+
+// State is what a rule remembers about the digits it has seen so far. The
+// fewer states a rule uses, the more work counting can share.
+type State uint64
+
+// Rule checks a number one digit at a time, most significant first.
+type Rule interface {
+	// Start is the state before any digit.
+	Start() State
+	// Next returns the state after digit d, or false if no number continuing
+	// this way can match.
+	Next(s State, d int) (State, bool)
+	// Accept reports whether a number ending in state s matches.
+	Accept(s State) bool
+	// String describes the rule, in the syntax Parse reads where it can.
+	String() string
+}
+
+// ruleFuncs is a Rule built from functions.
+type ruleFuncs struct {
+	name   string
+	start  State
+	next   func(s State, d int) (State, bool)
+	accept func(s State) bool
+}
+
+func (r ruleFuncs) Start() State                      { return r.start }
+func (r ruleFuncs) Next(s State, d int) (State, bool) { return r.next(s, d) }
+func (r ruleFuncs) Accept(s State) bool               { return r.accept(s) }
+func (r ruleFuncs) String() string                    { return r.name }
+
+// Length matches numbers of exactly n digits.
+func Length(n int) Rule {
+	return ruleFuncs{
+		name: fmt.Sprintf("len=%d", n),
+		next: func(s State, d int) (State, bool) {
+			return s + 1, int(s) < n
+		},
+		accept: func(s State) bool {
+			return int(s) == n
+		},
+	}
+}
+
+// NonDecreasing matches numbers whose digits never decrease from left to
+// right.
+func NonDecreasing() Rule {
+	// The state is the last digit plus one, zero before any digit.
+	return ruleFuncs{
+		name: "nondecreasing",
+		next: func(s State, d int) (State, bool) {
+			return State(d + 1), s == 0 || d >= int(s)-1
+		},
+		accept: func(s State) bool {
+			return true
+		},
+	}
+}
+
+// Run is a group of the same digit repeated Length times in a row.
+type Run struct {
+	Digit  int
+	Length int
+}
+
+// AnyRun matches numbers with at least one run for which f is true. name
+// describes f.
+func AnyRun(name string, f func(r Run) bool) Rule {
+	return runRule(name, f, false)
+}
+
+// AllRuns matches numbers for which f is true of every run. name describes
+// f.
+func AllRuns(name string, f func(r Run) bool) Rule {
+	return runRule(name, f, true)
+}
+
+// MinRun matches numbers with a digit repeated at least n times in a row.
+func MinRun(n int) Rule {
+	return AnyRun(fmt.Sprintf("run>=%d", n), func(r Run) bool { return r.Length >= n })
+}
+
+// ExactRun matches numbers with a digit repeated exactly n times in a row.
+func ExactRun(n int) Rule {
+	return AnyRun(fmt.Sprintf("exactrun=%d", n), func(r Run) bool { return r.Length == n })
+}
+
+// MaxRun matches numbers with no digit repeated more than n times in a row.
+func MaxRun(n int) Rule {
+	return AllRuns(fmt.Sprintf("maxrun=%d", n), func(r Run) bool { return r.Length <= n })
+}
+
+// Repeated matches numbers with two adjacent digits the same.
+func Repeated() Rule {
+	return MinRun(2)
+}
+
+// IsolatedPair matches numbers with two adjacent digits the same that are
+// not part of a longer run.
+func IsolatedPair() Rule {
+	return ExactRun(2)
+}
+
+// runState packs the current run and whether f has been true of any (or
+// every) finished run.
+type runState struct {
+	run    Run
+	inRun  bool
+	result bool
+}
+
+func (rs runState) pack() State {
+	s := State(rs.run.Digit) | State(rs.run.Length)<<4
+	if rs.inRun {
+		s |= 1 << 62
+	}
+	if rs.result {
+		s |= 1 << 63
+	}
+	return s
+}
+
+func unpack(s State) runState {
+	return runState{
+		run:    Run{Digit: int(s & 0xf), Length: int(s>>4) & (1<<58 - 1)},
+		inRun:  s&(1<<62) != 0,
+		result: s&(1<<63) != 0,
+	}
+}
+
+// runRule checks f of every run, combining the results with OR or, when all
+// is set, AND.
+func runRule(name string, f func(r Run) bool, all bool) Rule {
+	finish := func(rs runState) bool {
+		if !rs.inRun {
+			return rs.result
+		}
+		if all {
+			return rs.result && f(rs.run)
+		}
+		return rs.result || f(rs.run)
+	}
+	return ruleFuncs{
+		name:  name,
+		start: runState{result: all}.pack(),
+		next: func(s State, d int) (State, bool) {
+			rs := unpack(s)
+			if rs.inRun && rs.run.Digit == d {
+				rs.run.Length++
+				return rs.pack(), true
+			}
+			rs.result = finish(rs)
+			rs.run, rs.inRun = Run{Digit: d, Length: 1}, true
+			// Once a run fails f, no number continuing this way has all
+			// runs passing.
+			return rs.pack(), !all || rs.result
+		},
+		accept: func(s State) bool {
+			return finish(unpack(s))
+		},
+	}
+}
@@ -0,0 +1,244 @@
+// Package search finds paths and distances in graphs described by their
+// neighbors: breadth first search, Dijkstra, A* and flood fill.
+package search
+
+import "container/heap"
+
+// Graph is an unweighted graph.
+type Graph[N comparable] interface {
+	Neighbors(n N) []N
+}
+
+// WeightedGraph is a graph whose edges have a non-negative cost.
+type WeightedGraph[N comparable] interface {
+	Graph[N]
+	Cost(from, to N) int
+}
+
+// GraphFunc adapts a neighbors function to a Graph.
+type GraphFunc[N comparable] func(n N) []N
+
+func (f GraphFunc[N]) Neighbors(n N) []N {
+	return f(n)
+}
+
+// Unweighted gives every edge of g a cost of 1.
+func Unweighted[N comparable](g Graph[N]) WeightedGraph[N] {
+	return unweighted[N]{g}
+}
+
+type unweighted[N comparable] struct {
+	Graph[N]
+}
+
+func (unweighted[N]) Cost(from, to N) int {
+	return 1
+}
+
+// Tree holds the result of searching outward from one or more sources: the
+// distance to every reached node and the node it was first reached from.
+type Tree[N comparable] struct {
+	Dist map[N]int
+	Prev map[N]N
+}
+
+func newTree[N comparable]() *Tree[N] {
+	return &Tree[N]{Dist: make(map[N]int), Prev: make(map[N]N)}
+}
+
+// PathTo returns the path from the nearest source to n, both included, or
+// nil if n was not reached.
+func (t *Tree[N]) PathTo(n N) []N {
+	if _, ok := t.Dist[n]; !ok {
+		return nil
+	}
+	path := []N{n}
+	for {
+		prev, ok := t.Prev[n]
+		if !ok {
+			break
+		}
+		path = append(path, prev)
+		n = prev
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Farthest returns the greatest distance to any reached node.
+func (t *Tree[N]) Farthest() int {
+	farthest := 0
+	for _, d := range t.Dist {
+		if d > farthest {
+			farthest = d
+		}
+	}
+	return farthest
+}
+
+// BFSTree searches breadth first from every source at once, giving the
+// distance from each reached node to its nearest source.
+func BFSTree[N comparable](g Graph[N], sources ...N) *Tree[N] {
+	t := newTree[N]()
+	bfs(g, t, sources, nil)
+	return t
+}
+
+// BFS returns a shortest path from start to the first node satisfying goal.
+func BFS[N comparable](g Graph[N], start N, goal func(n N) bool) ([]N, bool) {
+	t := newTree[N]()
+	if found, ok := bfs(g, t, []N{start}, goal); ok {
+		return t.PathTo(found), true
+	}
+	return nil, false
+}
+
+func bfs[N comparable](g Graph[N], t *Tree[N], sources []N, goal func(n N) bool) (N, bool) {
+	queue := make([]N, 0, len(sources))
+	for _, s := range sources {
+		if _, seen := t.Dist[s]; !seen {
+			t.Dist[s] = 0
+			queue = append(queue, s)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if goal != nil && goal(n) {
+			return n, true
+		}
+		for _, next := range g.Neighbors(n) {
+			if _, seen := t.Dist[next]; seen {
+				continue
+			}
+			t.Dist[next] = t.Dist[n] + 1
+			t.Prev[next] = n
+			queue = append(queue, next)
+		}
+	}
+
+	var none N
+	return none, false
+}
+
+// FloodFill returns the nodes reachable from the sources in breadth first
+// layers: the sources, then their unvisited neighbors, and so on. Layer i
+// holds the nodes i steps from the nearest source.
+func FloodFill[N comparable](g Graph[N], sources ...N) [][]N {
+	seen := make(map[N]bool)
+	var layer []N
+	for _, s := range sources {
+		if !seen[s] {
+			seen[s] = true
+			layer = append(layer, s)
+		}
+	}
+
+	var layers [][]N
+	for len(layer) > 0 {
+		layers = append(layers, layer)
+		var next []N
+		for _, n := range layer {
+			for _, neighbor := range g.Neighbors(n) {
+				if !seen[neighbor] {
+					seen[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		layer = next
+	}
+	return layers
+}
+
+// DijkstraTree finds the cheapest distance from the nearest source to every
+// reachable node.
+func DijkstraTree[N comparable](g WeightedGraph[N], sources ...N) *Tree[N] {
+	t := newTree[N]()
+	astar(g, t, sources, nil, nil)
+	return t
+}
+
+// Dijkstra returns a cheapest path from start to the first node satisfying
+// goal, and its cost.
+func Dijkstra[N comparable](g WeightedGraph[N], start N, goal func(n N) bool) ([]N, int, bool) {
+	return AStar(g, start, goal, nil)
+}
+
+// AStar returns a cheapest path from start to the first node satisfying goal,
+// and its cost. The heuristic estimates the remaining cost from a node and
+// must be consistent: it never drops by more than the cost of an edge, and
+// is 0 at a goal. Nodes are never reopened once expanded, so a heuristic that
+// is only admissible can give a path that is not the cheapest. A nil
+// heuristic makes this Dijkstra's algorithm.
+func AStar[N comparable](g WeightedGraph[N], start N, goal func(n N) bool, heuristic func(n N) int) ([]N, int, bool) {
+	t := newTree[N]()
+	found, ok := astar(g, t, []N{start}, goal, heuristic)
+	if !ok {
+		return nil, 0, false
+	}
+	return t.PathTo(found), t.Dist[found], true
+}
+
+func astar[N comparable](g WeightedGraph[N], t *Tree[N], sources []N, goal func(n N) bool, heuristic func(n N) int) (N, bool) {
+	if heuristic == nil {
+		heuristic = func(n N) int { return 0 }
+	}
+
+	done := make(map[N]bool)
+	q := &queue[N]{}
+	for _, s := range sources {
+		t.Dist[s] = 0
+		heap.Push(q, item[N]{node: s, priority: heuristic(s)})
+	}
+
+	for q.Len() > 0 {
+		n := heap.Pop(q).(item[N]).node
+		if done[n] {
+			continue
+		}
+		done[n] = true
+
+		if goal != nil && goal(n) {
+			return n, true
+		}
+
+		for _, next := range g.Neighbors(n) {
+			if done[next] {
+				continue
+			}
+			dist := t.Dist[n] + g.Cost(n, next)
+			if d, seen := t.Dist[next]; seen && d <= dist {
+				continue
+			}
+			t.Dist[next] = dist
+			t.Prev[next] = n
+			heap.Push(q, item[N]{node: next, priority: dist + heuristic(next)})
+		}
+	}
+
+	var none N
+	return none, false
+}
+
+type item[N any] struct {
+	node     N
+	priority int
+}
+
+type queue[N any] []item[N]
+
+func (q queue[N]) Len() int            { return len(q) }
+func (q queue[N]) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q queue[N]) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue[N]) Push(x interface{}) { *q = append(*q, x.(item[N])) }
+
+func (q *queue[N]) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
@@ -0,0 +1,148 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+type point struct {
+	x, y int
+}
+
+// maze parses a grid where '#' is a wall and digits are the cost of entering
+// a cell ('.' costs 1).
+type maze []string
+
+func (m maze) Neighbors(p point) []point {
+	var neighbors []point
+	for _, d := range []point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+		n := point{p.x + d.x, p.y + d.y}
+		if n.y >= 0 && n.y < len(m) && n.x >= 0 && n.x < len(m[n.y]) && m[n.y][n.x] != '#' {
+			neighbors = append(neighbors, n)
+		}
+	}
+	return neighbors
+}
+
+func (m maze) Cost(from, to point) int {
+	if c := m[to.y][to.x]; c >= '1' && c <= '9' {
+		return int(c - '0')
+	}
+	return 1
+}
+
+var testMaze = maze{
+	"..9..",
+	".#9#.",
+	".#..#",
+	"...#.",
+}
+
+func at(target point) func(p point) bool {
+	return func(p point) bool { return p == target }
+}
+
+func TestBFS(t *testing.T) {
+	path, ok := BFS[point](testMaze, point{0, 0}, at(point{3, 2}))
+	if !ok {
+		t.Fatal("Expected a path")
+	}
+	expected := []point{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {3, 2}}
+	if !reflect.DeepEqual(path, expected) {
+		t.Errorf("Expected %v, got %v", expected, path)
+	}
+
+	if _, ok := BFS[point](testMaze, point{0, 0}, at(point{4, 3})); ok {
+		t.Error("Expected no path to a walled in cell")
+	}
+}
+
+func TestBFSTree(t *testing.T) {
+	tree := BFSTree[point](testMaze, point{0, 0}, point{4, 0})
+	specs := map[point]int{
+		{0, 0}: 0,
+		{4, 1}: 1,
+		{2, 0}: 2,
+		{0, 3}: 3,
+		{3, 2}: 5,
+	}
+	for p, expected := range specs {
+		if d := tree.Dist[p]; d != expected {
+			t.Errorf("Expected %v to be %d away, got %d", p, expected, d)
+		}
+	}
+	if _, ok := tree.Dist[point{4, 3}]; ok {
+		t.Error("Expected a walled in cell to be unreached")
+	}
+	if path := tree.PathTo(point{4, 2}); path != nil {
+		t.Errorf("Expected no path to a wall, got %v", path)
+	}
+	if path := tree.PathTo(point{4, 1}); !reflect.DeepEqual(path, []point{{4, 0}, {4, 1}}) {
+		t.Errorf("Expected a path from the nearest source, got %v", path)
+	}
+	if tree.Farthest() != 5 {
+		t.Errorf("Expected the farthest cell to be 5 away, got %d", tree.Farthest())
+	}
+}
+
+func TestDijkstraAndAStar(t *testing.T) {
+	// Going around the left avoids the expensive 9s.
+	expected := []point{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {1, 3}, {2, 3}, {2, 2}, {3, 2}}
+
+	path, cost, ok := Dijkstra[point](testMaze, point{0, 0}, at(point{3, 2}))
+	if !ok || cost != 7 || !reflect.DeepEqual(path, expected) {
+		t.Errorf("Expected %v costing 7, got %v costing %d", expected, path, cost)
+	}
+
+	manhattan := func(p point) int {
+		return abs(p.x-3) + abs(p.y-2)
+	}
+	path, cost, ok = AStar[point](testMaze, point{0, 0}, at(point{3, 2}), manhattan)
+	if !ok || cost != 7 || !reflect.DeepEqual(path, expected) {
+		t.Errorf("Expected %v costing 7, got %v costing %d", expected, path, cost)
+	}
+
+	if _, _, ok := AStar[point](testMaze, point{0, 0}, at(point{4, 3}), manhattan); ok {
+		t.Error("Expected no path to a walled in cell")
+	}
+
+	tree := DijkstraTree[point](testMaze, point{0, 0})
+	if tree.Dist[point{2, 1}] != 15 || tree.Dist[point{4, 1}] != 13 {
+		t.Errorf("Unexpected distances %v", tree.Dist)
+	}
+
+	_, cost, _ = Dijkstra(Unweighted[point](testMaze), point{0, 0}, at(point{3, 2}))
+	if cost != 5 {
+		t.Errorf("Expected an unweighted cost of 5, got %d", cost)
+	}
+}
+
+func TestFloodFill(t *testing.T) {
+	// A long line would recurse deeply in a naive flood fill.
+	const n = 1000000
+	line := GraphFunc[int](func(i int) []int {
+		var neighbors []int
+		if i > 0 {
+			neighbors = append(neighbors, i-1)
+		}
+		if i < n-1 {
+			neighbors = append(neighbors, i+1)
+		}
+		return neighbors
+	})
+
+	layers := FloodFill[int](line, 0, n-1)
+	if len(layers) != n/2 {
+		t.Errorf("Expected %d layers, got %d", n/2, len(layers))
+	}
+	if !reflect.DeepEqual(layers[0], []int{0, n - 1}) || !reflect.DeepEqual(layers[1], []int{1, n - 2}) {
+		t.Errorf("Unexpected first layers %v %v", layers[0], layers[1])
+	}
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
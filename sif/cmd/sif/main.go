@@ -0,0 +1,133 @@
+// Command sif converts pictures to and from the Space Image Format.
+package main
+
+import (
+	"adventofcode/imaging"
+	"adventofcode/sif"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"time"
+)
+
+const usage = `usage: sif <command> [arguments]
+
+commands:
+  encode [--layers n] [--seed n] <picture.png|gif> [out.txt]
+                          write a picture as SIF digits, in black, white and
+                          transparent, spread over n layers
+  decode --width w --height h [--scale n] <in.txt> <out.png>
+                          draw SIF digits as seen from the top
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "encode":
+		err = encode(os.Args[2:], os.Stdout, os.Stderr)
+	case "decode":
+		err = decode(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sif %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func encode(args []string, out, info io.Writer) error {
+	fs := flag.NewFlagSet("encode", flag.ContinueOnError)
+	layers := fs.Int("layers", 1, "number of layers to spread the picture over")
+	seed := fs.Int64("seed", 0, "random seed for --layers (default the current time)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		return errors.New("expected <picture> [out.txt]")
+	}
+	if *layers < 1 {
+		return fmt.Errorf("invalid number of layers %d", *layers)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return err
+	}
+
+	img := sif.FromImage(src)
+	if *layers > 1 {
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+		}
+		if img, err = img.Obfuscate(*layers, rand.New(rand.NewSource(*seed))); err != nil {
+			return err
+		}
+	}
+
+	digits, err := img.Encode()
+	if err != nil {
+		return err
+	}
+	digits += "\n"
+	if fs.NArg() == 2 {
+		err = ioutil.WriteFile(fs.Arg(1), []byte(digits), 0644)
+	} else {
+		_, err = io.WriteString(out, digits)
+	}
+	if err == nil {
+		fmt.Fprintf(info, "%dx%d, %d layers\n", img.Width, img.Height, len(img.Layers))
+	}
+	return err
+}
+
+func decode(args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	width := fs.Int("width", 25, "layer width")
+	height := fs.Int("height", 6, "layer height")
+	scale := fs.Int("scale", 8, "pixels per SIF pixel")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("expected <in.txt> <out.png>")
+	}
+
+	data, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	img, err := sif.Decode(string(data), *width, *height)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	err = imaging.WritePNG(f, img.ToImage(), *scale)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
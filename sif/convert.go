@@ -0,0 +1,76 @@
+package sif
+
+import (
+	"adventofcode/imaging"
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+)
+
+var colors = map[int]color.Color{
+	Black: color.Black,
+	White: color.White,
+}
+
+// FromImage turns a picture into a single layer image. Mostly transparent
+// pixels become Transparent and the rest Black or White, whichever is
+// closer.
+func FromImage(src image.Image) *Image {
+	b := src.Bounds()
+	img := &Image{Width: b.Dx(), Height: b.Dy(), Layers: [][]int{make([]int, b.Dx()*b.Dy())}}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBA64Model.Convert(src.At(x, y)).(color.NRGBA64)
+			v := Black
+			switch {
+			case c.A < 0x8000:
+				v = Transparent
+			case color.Gray16Model.Convert(c).(color.Gray16).Y >= 0x8000:
+				v = White
+			}
+			img.Layers[0][(y-b.Min.Y)*img.Width+x-b.Min.X] = v
+		}
+	}
+	return img
+}
+
+// ToImage draws the image as seen from the top, one pixel per pixel.
+func (img *Image) ToImage() *image.RGBA {
+	flat := img.Flatten()
+	return imaging.Image[int](flat, flat.Bounds(), imaging.Colors(colors, color.Transparent))
+}
+
+// Obfuscate spreads the image over the given number of layers. Each pixel is
+// transparent above a randomly chosen layer holding its color and random
+// below it, so the image still flattens to the same picture.
+func (img *Image) Obfuscate(layers int, r *rand.Rand) (*Image, error) {
+	if layers < 1 {
+		return nil, fmt.Errorf("sif: invalid number of layers %d", layers)
+	}
+	flat := img.Flatten()
+	out := &Image{Width: img.Width, Height: img.Height, Layers: make([][]int, layers)}
+	for l := range out.Layers {
+		out.Layers[l] = make([]int, img.Width*img.Height)
+	}
+	for i, v := range flat.Cells {
+		if v == Transparent {
+			for l := range out.Layers {
+				out.Layers[l][i] = Transparent
+			}
+			continue
+		}
+		shown := r.Intn(layers)
+		for l := range out.Layers {
+			switch {
+			case l < shown:
+				out.Layers[l][i] = Transparent
+			case l == shown:
+				out.Layers[l][i] = v
+			default:
+				out.Layers[l][i] = r.Intn(3)
+			}
+		}
+	}
+	return out, nil
+}
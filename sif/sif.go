@@ -0,0 +1,119 @@
+// Package sif decodes and encodes the Space Image Format: layers of digits,
+// each width by height, stacked so the first opaque pixel shows.
+package sif
+
+import (
+	"adventofcode/grid"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Pixel colors.
+const (
+	Black       = 0
+	White       = 1
+	Transparent = 2
+)
+
+// Image is a stack of layers, the first on top. Each layer holds its pixels
+// row by row.
+type Image struct {
+	Width  int
+	Height int
+	Layers [][]int
+}
+
+// Decode reads an image of the given size from a string of digits.
+// Surrounding whitespace is ignored.
+func Decode(input string, width, height int) (*Image, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("sif: invalid size %dx%d", width, height)
+	}
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errors.New("sif: no layers")
+	}
+	size := width * height
+	if len(input)%size != 0 {
+		return nil, fmt.Errorf("sif: %d digits do not make whole %dx%d layers", len(input), width, height)
+	}
+
+	img := &Image{Width: width, Height: height, Layers: make([][]int, len(input)/size)}
+	for l := range img.Layers {
+		layer := make([]int, size)
+		for i := range layer {
+			c := input[l*size+i]
+			if c < '0' || c > '9' {
+				return nil, fmt.Errorf("sif: invalid digit %q at %d", c, l*size+i)
+			}
+			layer[i] = int(c - '0')
+		}
+		img.Layers[l] = layer
+	}
+	return img, nil
+}
+
+// Encode writes the image as the digits Decode reads. Every layer must be
+// Width by Height and every pixel a single digit, 0 to 9.
+func (img *Image) Encode() (string, error) {
+	size := img.Width * img.Height
+	var b strings.Builder
+	b.Grow(len(img.Layers) * size)
+	for l, layer := range img.Layers {
+		if len(layer) != size {
+			return "", fmt.Errorf("sif: layer %d has %d pixels, not %dx%d", l, len(layer), img.Width, img.Height)
+		}
+		for i, v := range layer {
+			if v < 0 || v > 9 {
+				return "", fmt.Errorf("sif: pixel %d of layer %d is %d, not a digit", i, l, v)
+			}
+			b.WriteByte(byte('0' + v))
+		}
+	}
+	return b.String(), nil
+}
+
+// Layer returns layer l as a grid sharing the layer's pixels.
+func (img *Image) Layer(l int) *grid.Dense[int] {
+	return &grid.Dense[int]{Rows: img.Height, Cols: img.Width, Cells: img.Layers[l]}
+}
+
+// Flatten returns the image as seen from the top: each pixel is that of the
+// first layer where it is not transparent.
+func (img *Image) Flatten() *grid.Dense[int] {
+	flat := grid.NewDense(img.Height, img.Width, Transparent)
+	for i := range flat.Cells {
+		for _, layer := range img.Layers {
+			if layer[i] != Transparent {
+				flat.Cells[i] = layer[i]
+				break
+			}
+		}
+	}
+	return flat
+}
+
+// Histogram counts each digit in layer l.
+func (img *Image) Histogram(l int) ([10]int, error) {
+	var h [10]int
+	if l < 0 || l >= len(img.Layers) {
+		return h, fmt.Errorf("sif: no layer %d in %d layers", l, len(img.Layers))
+	}
+	for _, v := range img.Layers[l] {
+		h[v]++
+	}
+	return h, nil
+}
+
+// Checksum multiplies the number of 1 digits by the number of 2 digits in
+// the layer with the fewest 0 digits.
+func (img *Image) Checksum() int {
+	var fewest [10]int
+	for l := range img.Layers {
+		if h, _ := img.Histogram(l); l == 0 || h[0] < fewest[0] {
+			fewest = h
+		}
+	}
+	return fewest[1] * fewest[2]
+}
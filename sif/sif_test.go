@@ -0,0 +1,144 @@
+package sif
+
+import (
+	"adventofcode/grid"
+	"image"
+	"image/color"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	img, err := Decode("123456789012\n", 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]int{{1, 2, 3, 4, 5, 6}, {7, 8, 9, 0, 1, 2}}
+	if !reflect.DeepEqual(img.Layers, expected) {
+		t.Errorf("Expected layers %v, got %v", expected, img.Layers)
+	}
+	if v := img.Layer(1).Get(grid.Point{Row: 1, Col: 0}); v != 0 {
+		t.Errorf("Expected 0 at the start of the second row of layer 2, got %d", v)
+	}
+	if s, err := img.Encode(); err != nil || s != "123456789012" {
+		t.Errorf("Expected to encode back to the input, got %q, %v", s, err)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	specs := map[string]*Image{
+		"above 9":     {Width: 2, Height: 1, Layers: [][]int{{1, 10}}},
+		"negative":    {Width: 2, Height: 1, Layers: [][]int{{-1, 0}}},
+		"short layer": {Width: 2, Height: 1, Layers: [][]int{{1, 0}, {1}}},
+	}
+
+	for name, img := range specs {
+		t.Run(name, func(t *testing.T) {
+			if s, err := img.Encode(); err == nil {
+				t.Errorf("Expected an error, got %q", s)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	specs := []struct {
+		input         string
+		width, height int
+		expected      string
+	}{
+		{"1234567890123", 3, 2, "do not make whole"},
+		{"12345a", 3, 2, "invalid digit 'a' at 5"},
+		{"12 456", 3, 2, "invalid digit ' ' at 2"},
+		{"\n", 3, 2, "no layers"},
+		{"123456", 0, 2, "invalid size"},
+	}
+	for _, spec := range specs {
+		_, err := Decode(spec.input, spec.width, spec.height)
+		if err == nil || !strings.Contains(err.Error(), spec.expected) {
+			t.Errorf("%q: expected an error containing %q, got %v", spec.input, spec.expected, err)
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	img, err := Decode("0222112222120000", 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flat := img.Flatten(); !reflect.DeepEqual(flat.Cells, []int{Black, White, White, Black}) {
+		t.Errorf("Expected [0 1 1 0], got %v", flat.Cells)
+	}
+
+	img, _ = Decode("2212", 2, 1)
+	if flat := img.Flatten(); !reflect.DeepEqual(flat.Cells, []int{1, Transparent}) {
+		t.Errorf("Expected a pixel transparent in every layer to stay transparent, got %v", flat.Cells)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	img, err := Decode("001122"+"011222"+"000012", 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, err := img.Histogram(1); err != nil || h != [10]int{1, 2, 3} {
+		t.Errorf("Expected histogram [1 2 3 0...], got %v, %v", h, err)
+	}
+	for _, l := range []int{-1, 3} {
+		if _, err := img.Histogram(l); err == nil {
+			t.Errorf("Expected an error for layer %d", l)
+		}
+	}
+	if c := img.Checksum(); c != 6 {
+		t.Errorf("Expected checksum 6, got %d", c)
+	}
+}
+
+func TestImageConversion(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(10, 20, 13, 22))
+	src.Set(10, 20, color.White)
+	src.Set(11, 20, color.NRGBA{200, 200, 200, 255})
+	src.Set(12, 20, color.NRGBA{60, 60, 60, 255})
+	src.Set(10, 21, color.Black)
+	src.Set(11, 21, color.NRGBA{255, 255, 255, 10})
+
+	img := FromImage(src)
+	expected := []int{White, White, Black, Black, Transparent, Transparent}
+	if img.Width != 3 || img.Height != 2 || !reflect.DeepEqual(img.Layers, [][]int{expected}) {
+		t.Fatalf("Expected a 3x2 layer %v, got %dx%d %v", expected, img.Width, img.Height, img.Layers)
+	}
+
+	for _, layers := range []int{0, -1} {
+		if _, err := img.Obfuscate(layers, rand.New(rand.NewSource(1))); err == nil {
+			t.Errorf("Expected an error obfuscating into %d layers", layers)
+		}
+	}
+	layered, err := img.Obfuscate(5, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layered.Layers) != 5 {
+		t.Fatalf("Expected 5 layers, got %d", len(layered.Layers))
+	}
+	if flat := layered.Flatten(); !reflect.DeepEqual(flat.Cells, expected) {
+		t.Errorf("Expected the layers to flatten to %v, got %v", expected, flat.Cells)
+	}
+
+	digits, err := layered.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(digits, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pic := decoded.ToImage()
+	for i, v := range expected {
+		c := pic.RGBAAt(i%3, i/3)
+		if (v == White && c != color.RGBA{255, 255, 255, 255}) || (v == Black && c != color.RGBA{0, 0, 0, 255}) || (v == Transparent && c.A != 0) {
+			t.Errorf("Pixel %d: expected %d, got %v", i, v, c)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseLines matches every non-blank line against re and appends a struct
+// built from the submatches to the slice dst points to. Named groups fill the
+// field of the same name (ignoring case); unnamed groups fill the exported
+// fields in order. Fields may be strings, bools, integers or floats.
+//
+//	var positions []Position
+//	err := utils.ParseLines(lines, regexp.MustCompile(`<x=(-?\d+), y=(-?\d+), z=(-?\d+)>`), &positions)
+func ParseLines(lines []string, re *regexp.Regexp, dst interface{}) error {
+	slice := reflect.ValueOf(dst)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice || slice.Elem().Type().Elem().Kind() != reflect.Struct {
+		return errors.New("utils: ParseLines needs a pointer to a slice of structs")
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	fields, err := fieldsForGroups(re, elemType)
+	if err != nil {
+		return err
+	}
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		matches := re.FindStringSubmatch(line)
+		if matches == nil {
+			return fmt.Errorf("line %d: %q does not match %s", i+1, line, re)
+		}
+
+		elem := reflect.New(elemType).Elem()
+		for group, field := range fields {
+			if err := set(elem.Field(field), matches[group+1]); err != nil {
+				return fmt.Errorf("line %d: %s: %v", i+1, elemType.Field(field).Name, err)
+			}
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return nil
+}
+
+// fieldsForGroups maps each submatch group to a field index.
+func fieldsForGroups(re *regexp.Regexp, t reflect.Type) (map[int]int, error) {
+	var exported []int
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			exported = append(exported, i)
+		}
+	}
+
+	fields := make(map[int]int)
+	for group, name := range re.SubexpNames()[1:] {
+		if name == "" {
+			if group >= len(exported) {
+				return nil, fmt.Errorf("utils: no field for group %d of %s", group+1, re)
+			}
+			fields[group] = exported[group]
+			continue
+		}
+
+		found := false
+		for _, i := range exported {
+			if strings.EqualFold(t.Field(i).Name, name) {
+				fields[group] = i
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("utils: no field named %s in %s", name, t)
+		}
+	}
+	return fields, nil
+}
+
+func set(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package utils reads and parses puzzle input. Each Read function reads a file
+// and parses it with the string function of the same name.
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+func readFile(filename string) (string, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Lines splits s into lines, dropping carriage returns and the newline at the
+// end of the input.
+func Lines(s string) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func ReadLines(filename string) ([]string, error) {
+	s, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Lines(s), nil
+}
+
+// Ints parses one integer per line, skipping blank lines.
+func Ints(s string) ([]int, error) {
+	var ints []int
+	for i, line := range Lines(s) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		v, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid integer %q", i+1, line)
+		}
+		ints = append(ints, v)
+	}
+	return ints, nil
+}
+
+func ReadInts(filename string) ([]int, error) {
+	s, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Ints(s)
+}
+
+// CSVInts parses comma separated integers. Whitespace around values and a
+// trailing comma are ignored.
+func CSVInts(s string) ([]int, error) {
+	var ints []int
+	fields := strings.Split(strings.TrimSpace(s), ",")
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" && i == len(fields)-1 {
+			break
+		}
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("value %d: invalid integer %q", i+1, field)
+		}
+		ints = append(ints, v)
+	}
+	return ints, nil
+}
+
+func ReadCSVInts(filename string) ([]int, error) {
+	s, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return CSVInts(s)
+}
+
+// Grid splits s into rows of runes.
+func Grid(s string) [][]rune {
+	lines := Lines(s)
+	grid := make([][]rune, len(lines))
+	for i, line := range lines {
+		grid[i] = []rune(line)
+	}
+	return grid
+}
+
+func ReadGrid(filename string) ([][]rune, error) {
+	s, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Grid(s), nil
+}
+
+// Digits parses a run of decimal digits, ignoring surrounding whitespace.
+func Digits(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	digits := make([]int, len(s))
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			return nil, fmt.Errorf("position %d: invalid digit %q", i+1, r)
+		}
+		digits[i] = int(r - '0')
+	}
+	return digits, nil
+}
+
+func ReadDigits(filename string) ([]int, error) {
+	s, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Digits(s)
+}
+
+// Blocks splits s into groups of lines separated by blank lines.
+func Blocks(s string) [][]string {
+	var blocks [][]string
+	var block []string
+	for _, line := range Lines(s) {
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+			}
+			block = nil
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func ReadBlocks(filename string) ([][]string, error) {
+	s, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Blocks(s), nil
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestInts(t *testing.T) {
+	actual, err := Ints("12\n14\r\n\n1969\n100756\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{12, 14, 1969, 100756}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	if _, err := Ints("12\nfourteen\n"); err == nil || err.Error() != `line 2: invalid integer "fourteen"` {
+		t.Errorf("Expected a line 2 error, got %v", err)
+	}
+}
+
+func TestCSVInts(t *testing.T) {
+	specs := map[string][]int{
+		"1,0,0,0,99":         {1, 0, 0, 0, 99},
+		"1, 0, -3,\n":        {1, 0, -3},
+		"109,19,204,-34\n\n": {109, 19, 204, -34},
+	}
+	for input, expected := range specs {
+		actual, err := CSVInts(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Input: %q. Expected %v, got %v", input, expected, actual)
+		}
+	}
+
+	if _, err := CSVInts("1,,2"); err == nil {
+		t.Error("Expected an error for an empty value")
+	}
+}
+
+func TestDigits(t *testing.T) {
+	actual, err := Digits("80871224\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{8, 0, 8, 7, 1, 2, 2, 4}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	if _, err := Digits("12a4"); err == nil {
+		t.Error("Expected an error for a non-digit")
+	}
+}
+
+func TestGridAndBlocks(t *testing.T) {
+	grid := Grid(".#.\n#.#\n")
+	if len(grid) != 2 || string(grid[1]) != "#.#" {
+		t.Errorf("Expected two rows, got %q", grid)
+	}
+
+	blocks := Blocks("a\nb\n\n\nc\n\n")
+	expected := [][]string{{"a", "b"}, {"c"}}
+	if !reflect.DeepEqual(blocks, expected) {
+		t.Errorf("Expected %q, got %q", expected, blocks)
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "input.txt")
+	if err := ioutil.WriteFile(filename, []byte("COM)B\nB)C\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := ReadLines(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, []string{"COM)B", "B)C"}) {
+		t.Errorf("Expected two lines, got %q", lines)
+	}
+
+	if _, err := ReadLines(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	type position struct {
+		X, Y, Z int
+	}
+
+	var positions []position
+	re := regexp.MustCompile(`<x=(-?\d+), y=(-?\d+), z=(-?\d+)>`)
+	if err := ParseLines([]string{"<x=-1, y=0, z=2>", "<x=2, y=-10, z=-7>", ""}, re, &positions); err != nil {
+		t.Fatal(err)
+	}
+	expected := []position{{-1, 0, 2}, {2, -10, -7}}
+	if !reflect.DeepEqual(positions, expected) {
+		t.Errorf("Expected %v, got %v", expected, positions)
+	}
+
+	type orbit struct {
+		Orbiter string
+		Orbitee string
+	}
+
+	var orbits []orbit
+	named := regexp.MustCompile(`^(?P<orbitee>\w+)\)(?P<orbiter>\w+)$`)
+	if err := ParseLines([]string{"COM)B"}, named, &orbits); err != nil {
+		t.Fatal(err)
+	}
+	if orbits[0] != (orbit{Orbiter: "B", Orbitee: "COM"}) {
+		t.Errorf("Expected B to orbit COM, got %+v", orbits[0])
+	}
+
+	if err := ParseLines([]string{"COM)B", "oops"}, named, &orbits); err == nil {
+		t.Error("Expected an error for a line that does not match")
+	}
+}
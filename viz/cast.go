@@ -0,0 +1,64 @@
+package viz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// castWriter writes asciicast v2: a JSON header line followed by one JSON
+// array per write to the terminal, [seconds, "o", data]. Frames may change
+// size, so events are kept until close and the header sized to fit them all.
+type castWriter struct {
+	w      io.Writer
+	header castHeader
+	events bytes.Buffer
+}
+
+type castHeader struct {
+	Version   int   `json:"version"`
+	Width     int   `json:"width"`
+	Height    int   `json:"height"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// event records data written at t seconds while f is on the screen.
+func (c *castWriter) event(t float64, data string, f *Frame, cellWidth int) error {
+	if c.header.Version == 0 {
+		c.header = castHeader{Version: 2, Timestamp: time.Now().Unix()}
+	}
+	if w := f.Cols * cellWidth; w > c.header.Width {
+		c.header.Width = w
+	}
+	// One row for the caption and one for the cursor left below it.
+	if h := f.Rows + 2; h > c.header.Height {
+		c.header.Height = h
+	}
+
+	if data == "" {
+		return nil
+	}
+	e, err := json.Marshal([]interface{}{math.Round(t*1e6) / 1e6, "o", data})
+	if err != nil {
+		return err
+	}
+	c.events.Write(e)
+	c.events.WriteByte('\n')
+	return nil
+}
+
+// close writes the header and every recorded event.
+func (c *castWriter) close() error {
+	header, err := json.Marshal(c.header)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.w, "%s\n", header); err != nil {
+		return err
+	}
+	_, err = c.events.WriteTo(c.w)
+	return err
+}
@@ -0,0 +1,80 @@
+// Package viz animates simulations in the terminal. Frames are drawn at a
+// steady rate, redrawing only the cells that changed, and can be captured in
+// memory for tests or recorded as asciicast v2 files.
+package viz
+
+import (
+	"adventofcode/grid"
+	"strings"
+)
+
+// Frame is one picture of a simulation: rows of cells, each drawn with a
+// string one screen cell wide, and an optional caption line below them.
+type Frame struct {
+	Rows    int
+	Cols    int
+	Cells   []string
+	Caption string
+}
+
+// NewFrame returns a frame with every cell set to blank.
+func NewFrame(rows, cols int, blank string) *Frame {
+	f := &Frame{Rows: rows, Cols: cols, Cells: make([]string, rows*cols)}
+	for i := range f.Cells {
+		f.Cells[i] = blank
+	}
+	return f
+}
+
+// FromGrid draws the cells of g inside bounds.
+func FromGrid[T any](g grid.Grid[T], bounds grid.Rect, r grid.Renderer[T]) *Frame {
+	f := &Frame{Rows: bounds.Rows(), Cols: bounds.Cols()}
+	f.Cells = make([]string, 0, f.Rows*f.Cols)
+	for row := bounds.Min.Row; row <= bounds.Max.Row; row++ {
+		for col := bounds.Min.Col; col <= bounds.Max.Col; col++ {
+			p := grid.Point{Row: row, Col: col}
+			v, ok := g.At(p)
+			f.Cells = append(f.Cells, r(p, v, ok))
+		}
+	}
+	return f
+}
+
+func (f *Frame) in(row, col int) bool {
+	return row >= 0 && row < f.Rows && col >= 0 && col < f.Cols
+}
+
+// At returns the cell at row, col, or "" outside the frame.
+func (f *Frame) At(row, col int) string {
+	if !f.in(row, col) {
+		return ""
+	}
+	return f.Cells[row*f.Cols+col]
+}
+
+// Set changes the cell at row, col. Cells outside the frame are ignored.
+func (f *Frame) Set(row, col int, s string) {
+	if f.in(row, col) {
+		f.Cells[row*f.Cols+col] = s
+	}
+}
+
+func (f *Frame) clone() *Frame {
+	c := *f
+	c.Cells = append([]string(nil), f.Cells...)
+	return &c
+}
+
+// String returns the frame as lines of text, caption included.
+func (f *Frame) String() string {
+	var b strings.Builder
+	for row := 0; row < f.Rows; row++ {
+		b.WriteString(strings.Join(f.Cells[row*f.Cols:(row+1)*f.Cols], ""))
+		b.WriteByte('\n')
+	}
+	if f.Caption != "" {
+		b.WriteString(f.Caption)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
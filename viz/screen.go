@@ -0,0 +1,159 @@
+package viz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Options configure a Screen.
+type Options struct {
+	// FPS is how many frames are shown per second. Draw blocks to keep to
+	// it; 0 draws frames as fast as they come.
+	FPS int
+	// CellWidth is how many terminal columns a cell takes, 2 for emoji.
+	CellWidth int
+	// Headless keeps a copy of every frame, see Frames.
+	Headless bool
+	// Record, if set, receives an asciicast v2 recording of the animation
+	// when the screen is closed.
+	Record io.Writer
+}
+
+// Screen draws frames to a terminal. After the first frame only the cells
+// that changed are redrawn.
+type Screen struct {
+	out    io.Writer
+	opts   Options
+	prev   *Frame
+	frames []*Frame
+	count  int
+	start  time.Time
+	next   time.Time
+	cast   *castWriter
+}
+
+// New returns a screen drawing to out, which may be nil to only capture or
+// record frames. Without a terminal to watch, Draw never waits.
+func New(out io.Writer, opts Options) *Screen {
+	if opts.CellWidth <= 0 {
+		opts.CellWidth = 1
+	}
+	s := &Screen{out: out, opts: opts}
+	if opts.Record != nil {
+		s.cast = &castWriter{w: opts.Record}
+	}
+	return s
+}
+
+// NewHeadless returns a screen that only captures frames in memory.
+func NewHeadless() *Screen {
+	return New(nil, Options{Headless: true})
+}
+
+// Draw shows f, waiting for its turn if the screen has an FPS. f may be
+// changed and drawn again afterwards.
+func (s *Screen) Draw(f *Frame) error {
+	now := time.Now()
+	if s.count == 0 {
+		s.start, s.next = now, now
+	}
+	if s.out != nil && s.opts.FPS > 0 {
+		if wait := s.next.Sub(now); wait > 0 {
+			time.Sleep(wait)
+		} else {
+			s.next = now
+		}
+		s.next = s.next.Add(time.Second / time.Duration(s.opts.FPS))
+	}
+
+	out := s.diff(f)
+	if s.out != nil {
+		if _, err := io.WriteString(s.out, out); err != nil {
+			return err
+		}
+	}
+	if s.cast != nil {
+		if err := s.cast.event(s.elapsed(), out, f, s.opts.CellWidth); err != nil {
+			return err
+		}
+	}
+
+	s.prev = f.clone()
+	if s.opts.Headless {
+		s.frames = append(s.frames, s.prev)
+	}
+	s.count++
+	return nil
+}
+
+// elapsed is the time of the current frame in the recording: its place in
+// the sequence when there is an FPS, otherwise the time since the first.
+func (s *Screen) elapsed() float64 {
+	if s.opts.FPS > 0 {
+		return float64(s.count) / float64(s.opts.FPS)
+	}
+	return time.Since(s.start).Seconds()
+}
+
+// diff returns the escape sequences that turn the previous frame into f.
+func (s *Screen) diff(f *Frame) string {
+	var b strings.Builder
+	if s.prev == nil || s.prev.Rows != f.Rows || s.prev.Cols != f.Cols {
+		// Hide the cursor, clear the screen and draw everything.
+		b.WriteString("\033[?25l\033[H\033[2J")
+		for row := 0; row < f.Rows; row++ {
+			b.WriteString(strings.Join(f.Cells[row*f.Cols:(row+1)*f.Cols], ""))
+			b.WriteString("\r\n")
+		}
+		b.WriteString(f.Caption)
+		return b.String()
+	}
+
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			if f.At(row, col) == s.prev.At(row, col) {
+				continue
+			}
+			// Redraw the whole run of changed cells after one cursor move.
+			end := col
+			for end < f.Cols && f.At(row, end) != s.prev.At(row, end) {
+				end++
+			}
+			fmt.Fprintf(&b, "\033[%d;%dH", row+1, col*s.opts.CellWidth+1)
+			b.WriteString(strings.Join(f.Cells[row*f.Cols+col:row*f.Cols+end], ""))
+			col = end
+		}
+	}
+	if f.Caption != s.prev.Caption {
+		fmt.Fprintf(&b, "\033[%d;1H%s\033[K", f.Rows+1, f.Caption)
+	}
+	return b.String()
+}
+
+// Frames returns every frame drawn by a headless screen.
+func (s *Screen) Frames() []*Frame {
+	return s.frames
+}
+
+// Close moves the cursor below the last frame and shows it again, then
+// writes the recording if there is one.
+func (s *Screen) Close() error {
+	if s.prev == nil {
+		return nil
+	}
+	out := fmt.Sprintf("\033[%d;1H\033[?25h\n", s.prev.Rows+2)
+	if s.out != nil {
+		if _, err := io.WriteString(s.out, out); err != nil {
+			return err
+		}
+	}
+	if s.cast != nil {
+		if err := s.cast.event(s.elapsed(), out, s.prev, s.opts.CellWidth); err != nil {
+			return err
+		}
+		return s.cast.close()
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package viz
+
+import (
+	"adventofcode/grid"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFromGrid(t *testing.T) {
+	g := grid.Sparse[int]{{Row: 0, Col: 0}: 1, {Row: 1, Col: 2}: 2}
+	f := FromGrid[int](g, g.Bounds(), grid.Symbols(map[int]string{1: "#", 2: "o"}, "."))
+	f.Caption = "score 3"
+
+	if expected := "#..\n..o\nscore 3\n"; f.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, f.String())
+	}
+	if f.At(1, 2) != "o" || f.At(5, 5) != "" {
+		t.Errorf("Unexpected cells %q", f.Cells)
+	}
+}
+
+func TestHeadless(t *testing.T) {
+	s := NewHeadless()
+	f := NewFrame(1, 3, ".")
+	for col := 0; col < 3; col++ {
+		f.Set(0, col, "#")
+		if err := s.Draw(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	frames := s.Frames()
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %d", len(frames))
+	}
+	for i, expected := range []string{"#..\n", "##.\n", "###\n"} {
+		if frames[i].String() != expected {
+			t.Errorf("Frame %d: expected %q, got %q", i, expected, frames[i].String())
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var b bytes.Buffer
+	s := New(&b, Options{CellWidth: 2})
+
+	f := NewFrame(2, 4, "  ")
+	s.Draw(f)
+	if !strings.Contains(b.String(), "\033[2J") {
+		t.Errorf("Expected the first frame to clear the screen, got %q", b.String())
+	}
+
+	b.Reset()
+	f.Set(1, 1, "🟩")
+	f.Set(1, 2, "🟩")
+	f.Set(0, 3, "⚽")
+	f.Caption = "Score: 1"
+	s.Draw(f)
+	expected := "\033[1;7H⚽\033[2;3H🟩🟩\033[3;1HScore: 1\033[K"
+	if b.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	s.Draw(f)
+	if b.Len() != 0 {
+		t.Errorf("Expected nothing to redraw, got %q", b.String())
+	}
+
+	b.Reset()
+	s.Draw(NewFrame(3, 4, "  "))
+	if !strings.Contains(b.String(), "\033[2J") {
+		t.Errorf("Expected a resized frame to be redrawn, got %q", b.String())
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var cast bytes.Buffer
+	s := New(nil, Options{FPS: 10, Record: &cast})
+
+	f := NewFrame(2, 3, ".")
+	s.Draw(f)
+	f.Set(0, 0, "#")
+	s.Draw(f)
+	s.Draw(f)
+	s.Close()
+
+	scanner := bufio.NewScanner(&cast)
+	scanner.Scan()
+	var header castHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != 2 || header.Width != 3 || header.Height != 4 {
+		t.Errorf("Unexpected header %+v", header)
+	}
+
+	var times []float64
+	for scanner.Scan() {
+		var e []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		if len(e) != 3 || e[1] != "o" {
+			t.Errorf("Unexpected event %v", e)
+		}
+		times = append(times, e[0].(float64))
+	}
+	// The unchanged third frame writes nothing.
+	if expected := []float64{0, 0.1, 0.3}; len(times) != 3 || times[0] != expected[0] || times[1] != expected[1] || times[2] != expected[2] {
+		t.Errorf("Expected events at %v, got %v", expected, times)
+	}
+}
+
+func TestRecordResized(t *testing.T) {
+	var cast bytes.Buffer
+	s := New(nil, Options{CellWidth: 2, Record: &cast})
+
+	s.Draw(NewFrame(2, 3, "  "))
+	s.Draw(NewFrame(5, 2, "  "))
+	s.Draw(NewFrame(1, 4, "  "))
+	if cast.Len() != 0 {
+		t.Errorf("Expected nothing recorded before closing, got %q", cast.String())
+	}
+	s.Close()
+
+	scanner := bufio.NewScanner(&cast)
+	scanner.Scan()
+	var header castHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Width != 8 || header.Height != 7 {
+		t.Errorf("Expected an 8x7 header to fit every frame, got %+v", header)
+	}
+}
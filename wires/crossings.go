@@ -0,0 +1,157 @@
+package wires
+
+import (
+	"adventofcode/grid"
+	"sort"
+)
+
+// Crossing is a point other than the origin that two wires both cover.
+// Steps is how far along each wire the point is first reached.
+type Crossing struct {
+	Point grid.Point
+	Steps [2]int
+}
+
+// Distance is the Manhattan distance of the crossing from the origin.
+func (c Crossing) Distance() int {
+	return c.Point.Manhattan(grid.Point{})
+}
+
+// Delay is the combined number of steps both wires take to reach the
+// crossing.
+func (c Crossing) Delay() int {
+	return c.Steps[0] + c.Steps[1]
+}
+
+// Crossings returns every point where a and b cross, closest to the origin
+// first. Wires running along each other cross at every point they share.
+func Crossings(a, b Wire) []Crossing {
+	idx := newIndex(b.Segments())
+	first := make(map[grid.Point]Crossing)
+	for _, s := range a.Segments() {
+		idx.each(s, func(t Segment) {
+			overlap, ok := intersect(s.Rect(), t.Rect())
+			if !ok {
+				return
+			}
+			for row := overlap.Min.Row; row <= overlap.Max.Row; row++ {
+				for col := overlap.Min.Col; col <= overlap.Max.Col; col++ {
+					p := grid.Point{Row: row, Col: col}
+					if p == (grid.Point{}) {
+						continue
+					}
+					c := Crossing{Point: p, Steps: [2]int{s.StepsTo(p), t.StepsTo(p)}}
+					if seen, ok := first[p]; ok {
+						c.Steps[0] = min(c.Steps[0], seen.Steps[0])
+						c.Steps[1] = min(c.Steps[1], seen.Steps[1])
+					}
+					first[p] = c
+				}
+			}
+		})
+	}
+
+	crossings := make([]Crossing, 0, len(first))
+	for _, c := range first {
+		crossings = append(crossings, c)
+	}
+	sort.Slice(crossings, func(i, j int) bool {
+		return closer(crossings[i].Point, crossings[j].Point)
+	})
+	return crossings
+}
+
+// closer orders points by distance from the origin, then by row and column.
+func closer(a, b grid.Point) bool {
+	da, db := a.Manhattan(grid.Point{}), b.Manhattan(grid.Point{})
+	if da != db {
+		return da < db
+	}
+	if a.Row != b.Row {
+		return a.Row < b.Row
+	}
+	return a.Col < b.Col
+}
+
+// Closest returns the crossing nearest the origin.
+func Closest(crossings []Crossing) (Crossing, bool) {
+	return best(crossings, Crossing.Distance)
+}
+
+// Quickest returns the crossing with the smallest combined delay.
+func Quickest(crossings []Crossing) (Crossing, bool) {
+	return best(crossings, Crossing.Delay)
+}
+
+func best(crossings []Crossing, score func(Crossing) int) (Crossing, bool) {
+	if len(crossings) == 0 {
+		return Crossing{}, false
+	}
+	b := crossings[0]
+	for _, c := range crossings[1:] {
+		if score(c) < score(b) {
+			b = c
+		}
+	}
+	return b, true
+}
+
+// index sorts segments by the row or column they stay on, so the segments
+// that may touch another can be found without checking every one.
+type index struct {
+	horizontal []Segment
+	vertical   []Segment
+}
+
+func newIndex(segments []Segment) index {
+	var idx index
+	for _, s := range segments {
+		if s.Horizontal() {
+			idx.horizontal = append(idx.horizontal, s)
+		} else {
+			idx.vertical = append(idx.vertical, s)
+		}
+	}
+	sort.Slice(idx.horizontal, func(i, j int) bool { return idx.horizontal[i].From.Row < idx.horizontal[j].From.Row })
+	sort.Slice(idx.vertical, func(i, j int) bool { return idx.vertical[i].From.Col < idx.vertical[j].From.Col })
+	return idx
+}
+
+// each calls f with every indexed segment on a row or column that s covers.
+func (idx index) each(s Segment, f func(Segment)) {
+	r := s.Rect()
+	scan(idx.horizontal, func(t Segment) int { return t.From.Row }, r.Min.Row, r.Max.Row, f)
+	scan(idx.vertical, func(t Segment) int { return t.From.Col }, r.Min.Col, r.Max.Col, f)
+}
+
+// scan calls f with the segments whose key is within [lo, hi], segments
+// being sorted by key.
+func scan(segments []Segment, key func(Segment) int, lo, hi int, f func(Segment)) {
+	i := sort.Search(len(segments), func(i int) bool { return key(segments[i]) >= lo })
+	for ; i < len(segments) && key(segments[i]) <= hi; i++ {
+		f(segments[i])
+	}
+}
+
+// intersect returns the cells two rectangles share.
+func intersect(a, b grid.Rect) (grid.Rect, bool) {
+	r := grid.Rect{
+		Min: grid.Point{Row: max(a.Min.Row, b.Min.Row), Col: max(a.Min.Col, b.Min.Col)},
+		Max: grid.Point{Row: min(a.Max.Row, b.Max.Row), Col: min(a.Max.Col, b.Max.Col)},
+	}
+	return r, !r.Empty()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
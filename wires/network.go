@@ -0,0 +1,99 @@
+package wires
+
+import (
+	"adventofcode/grid"
+	"sort"
+)
+
+// Intersection is a point other than the origin that two or more wires
+// cover. Steps maps the index of each of those wires to how far along it the
+// point is first reached.
+type Intersection struct {
+	Point grid.Point
+	Steps map[int]int
+}
+
+// Wires returns the indexes of the wires crossing at the intersection.
+func (in Intersection) Wires() []int {
+	indexes := make([]int, 0, len(in.Steps))
+	for i := range in.Steps {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Distance is the Manhattan distance of the intersection from the origin.
+func (in Intersection) Distance() int {
+	return in.Point.Manhattan(grid.Point{})
+}
+
+// Delay is the combined number of steps every crossing wire takes to reach
+// the intersection.
+func (in Intersection) Delay() int {
+	delay := 0
+	for _, steps := range in.Steps {
+		delay += steps
+	}
+	return delay
+}
+
+// Intersections returns every point where at least two of ws cross, closest
+// to the origin first.
+func Intersections(ws []Wire) []Intersection {
+	points := make(map[grid.Point]Intersection)
+	for i := range ws {
+		for j := i + 1; j < len(ws); j++ {
+			for _, c := range Crossings(ws[i], ws[j]) {
+				in, ok := points[c.Point]
+				if !ok {
+					in = Intersection{Point: c.Point, Steps: make(map[int]int)}
+					points[c.Point] = in
+				}
+				in.Steps[i] = c.Steps[0]
+				in.Steps[j] = c.Steps[1]
+			}
+		}
+	}
+
+	intersections := make([]Intersection, 0, len(points))
+	for _, in := range points {
+		intersections = append(intersections, in)
+	}
+	sort.Slice(intersections, func(i, j int) bool {
+		return closer(intersections[i].Point, intersections[j].Point)
+	})
+	return intersections
+}
+
+// ClosestCrossedBy returns the intersection nearest the origin that at least
+// k wires cross.
+func ClosestCrossedBy(intersections []Intersection, k int) (Intersection, bool) {
+	var closest Intersection
+	found := false
+	for _, in := range intersections {
+		if len(in.Steps) >= k && (!found || in.Distance() < closest.Distance()) {
+			closest, found = in, true
+		}
+	}
+	return closest, found
+}
+
+// Pair is two wires by index, A < B.
+type Pair struct {
+	A, B int
+}
+
+// QuickestPairs returns, for each pair of ws that cross, the crossing with
+// the smallest combined delay.
+func QuickestPairs(ws []Wire) map[Pair]Crossing {
+	quickest := make(map[Pair]Crossing)
+	for i := range ws {
+		for j := i + 1; j < len(ws); j++ {
+			if c, ok := Quickest(Crossings(ws[i], ws[j])); ok {
+				quickest[Pair{i, j}] = c
+			}
+		}
+	}
+	return quickest
+}
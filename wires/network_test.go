@@ -0,0 +1,63 @@
+package wires
+
+import (
+	"adventofcode/grid"
+	"reflect"
+	"testing"
+)
+
+func TestIntersections(t *testing.T) {
+	var ws []Wire
+	for _, line := range []string{"R5", "U1,R2,D2", "D1,R2,U2", "R1,D1"} {
+		ws = append(ws, mustParse(t, line))
+	}
+	intersections := Intersections(ws)
+
+	specs := []struct {
+		k        int
+		point    grid.Point
+		wires    []int
+		delay    int
+		expected bool
+	}{
+		{2, grid.Point{Row: 0, Col: 1}, []int{0, 3}, 2, true},
+		{3, grid.Point{Row: 0, Col: 2}, []int{0, 1, 2}, 10, true},
+		{4, grid.Point{}, nil, 0, false},
+	}
+	for _, spec := range specs {
+		in, ok := ClosestCrossedBy(intersections, spec.k)
+		if ok != spec.expected {
+			t.Errorf("k=%d: expected found to be %t", spec.k, spec.expected)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if in.Point != spec.point || !reflect.DeepEqual(in.Wires(), spec.wires) || in.Delay() != spec.delay {
+			t.Errorf("k=%d: expected %v crossed by %v with delay %d, got %v crossed by %v with delay %d",
+				spec.k, spec.point, spec.wires, spec.delay, in.Point, in.Wires(), in.Delay())
+		}
+	}
+}
+
+func TestQuickestPairs(t *testing.T) {
+	var ws []Wire
+	for _, line := range []string{"R5", "U1,R2,D2", "D1,R2,U2", "R1,D1"} {
+		ws = append(ws, mustParse(t, line))
+	}
+
+	delays := make(map[Pair]int)
+	for pair, c := range QuickestPairs(ws) {
+		delays[pair] = c.Delay()
+	}
+	expected := map[Pair]int{
+		{0, 1}: 6,
+		{0, 2}: 6,
+		{0, 3}: 2,
+		{1, 2}: 8,
+		{2, 3}: 4,
+	}
+	if !reflect.DeepEqual(delays, expected) {
+		t.Errorf("Expected %v, got %v", expected, delays)
+	}
+}
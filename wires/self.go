@@ -0,0 +1,84 @@
+package wires
+
+import (
+	"adventofcode/grid"
+	"sort"
+)
+
+// Visits returns every step count at which the wire is at p, in order.
+func (w Wire) Visits(p grid.Point) []int {
+	var visits []int
+	for _, s := range w.Segments() {
+		if s.Rect().Contains(p) {
+			steps := s.StepsTo(p)
+			// Consecutive segments both hold the corner between them.
+			if len(visits) == 0 || visits[len(visits)-1] != steps {
+				visits = append(visits, steps)
+			}
+		}
+	}
+	return visits
+}
+
+// StepsTo is how far along the wire p is first reached.
+func (w Wire) StepsTo(p grid.Point) (int, bool) {
+	visits := w.Visits(p)
+	if len(visits) == 0 {
+		return 0, false
+	}
+	return visits[0], true
+}
+
+// SelfCrossing is a point a wire visits more than once. Steps are the step
+// counts of each visit, in order.
+type SelfCrossing struct {
+	Point grid.Point
+	Steps []int
+}
+
+// SelfCrossings returns every point the wire visits more than once, the
+// origin included, closest to the origin first.
+func SelfCrossings(w Wire) []SelfCrossing {
+	segments := w.Segments()
+	idx := newIndex(segments)
+	visits := make(map[grid.Point]map[int]bool)
+	for _, s := range segments {
+		idx.each(s, func(t Segment) {
+			if t == s {
+				return
+			}
+			overlap, ok := intersect(s.Rect(), t.Rect())
+			if !ok {
+				return
+			}
+			for row := overlap.Min.Row; row <= overlap.Max.Row; row++ {
+				for col := overlap.Min.Col; col <= overlap.Max.Col; col++ {
+					p := grid.Point{Row: row, Col: col}
+					a, b := s.StepsTo(p), t.StepsTo(p)
+					if a == b {
+						continue
+					}
+					if visits[p] == nil {
+						visits[p] = make(map[int]bool)
+					}
+					visits[p][a] = true
+					visits[p][b] = true
+				}
+			}
+		})
+	}
+
+	crossings := make([]SelfCrossing, 0, len(visits))
+	for p, steps := range visits {
+		c := SelfCrossing{Point: p}
+		for s := range steps {
+			c.Steps = append(c.Steps, s)
+		}
+		sort.Ints(c.Steps)
+		crossings = append(crossings, c)
+	}
+	sort.Slice(crossings, func(i, j int) bool {
+		return closer(crossings[i].Point, crossings[j].Point)
+	})
+	return crossings
+}
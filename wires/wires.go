@@ -0,0 +1,89 @@
+// Package wires finds where wires laid out on a grid cross, working on the
+// straight segments of each wire rather than on every cell it covers.
+package wires
+
+import (
+	"adventofcode/grid"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Instruction is one straight run of a wire's path.
+type Instruction struct {
+	Direction grid.Direction
+	Steps     int
+}
+
+// Wire is laid out from the origin by following its path.
+type Wire struct {
+	Path []Instruction
+}
+
+// Parse reads a comma separated path such as "R8,U5,L5,D3".
+func Parse(line string) (Wire, error) {
+	var w Wire
+	for _, v := range strings.Split(strings.TrimSpace(line), ",") {
+		if len(v) < 2 {
+			return w, fmt.Errorf("wires: invalid instruction %q", v)
+		}
+		direction, err := grid.ParseDirection(v[:1])
+		if err != nil {
+			return w, err
+		}
+		steps, err := strconv.Atoi(v[1:])
+		if err != nil || steps < 0 {
+			return w, fmt.Errorf("wires: invalid steps in %q", v)
+		}
+		w.Path = append(w.Path, Instruction{Direction: direction, Steps: steps})
+	}
+	return w, nil
+}
+
+// Segment is a straight part of a wire from From to To. Steps is how far
+// along the wire From is.
+type Segment struct {
+	From  grid.Point
+	To    grid.Point
+	Steps int
+}
+
+// Segments lays the wire out from the origin.
+func (w Wire) Segments() []Segment {
+	segments := make([]Segment, 0, len(w.Path))
+	at := grid.Point{}
+	steps := 0
+	for _, instruction := range w.Path {
+		delta := instruction.Direction.Delta()
+		to := at.Add(grid.Point{Row: delta.Row * instruction.Steps, Col: delta.Col * instruction.Steps})
+		segments = append(segments, Segment{From: at, To: to, Steps: steps})
+		at = to
+		steps += instruction.Steps
+	}
+	return segments
+}
+
+// Bounds is the smallest rectangle holding the whole wire, origin included.
+func (w Wire) Bounds() grid.Rect {
+	r := grid.Rect{}
+	for _, s := range w.Segments() {
+		r = r.Extend(s.To)
+	}
+	return r
+}
+
+// Rect is the rectangle covered by the segment, which is one row or column.
+func (s Segment) Rect() grid.Rect {
+	return grid.Rect{Min: s.From, Max: s.From}.Extend(s.To)
+}
+
+// Horizontal reports whether the segment stays on one row. Segments of no
+// length are horizontal.
+func (s Segment) Horizontal() bool {
+	return s.From.Row == s.To.Row
+}
+
+// StepsTo is how far along the wire p is, for a p on the segment.
+func (s Segment) StepsTo(p grid.Point) int {
+	return s.Steps + s.From.Manhattan(p)
+}
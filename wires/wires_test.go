@@ -0,0 +1,175 @@
+package wires
+
+import (
+	"adventofcode/grid"
+	"reflect"
+	"testing"
+)
+
+func mustParse(t *testing.T, line string) Wire {
+	t.Helper()
+	w, err := Parse(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestParse(t *testing.T) {
+	w := mustParse(t, "R8,U5,L5,D3\n")
+	expected := []Instruction{{grid.Right, 8}, {grid.Up, 5}, {grid.Left, 5}, {grid.Down, 3}}
+	if len(w.Path) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, w.Path)
+	}
+	for i := range expected {
+		if w.Path[i] != expected[i] {
+			t.Errorf("Instruction %d: expected %v, got %v", i, expected[i], w.Path[i])
+		}
+	}
+
+	for _, line := range []string{"", "R", "X5", "U-1", "R1,,U2"} {
+		if _, err := Parse(line); err == nil {
+			t.Errorf("Expected an error parsing %q", line)
+		}
+	}
+}
+
+func TestWireBounds(t *testing.T) {
+	w := mustParse(t, "U3,D5,L10,R15")
+	expected := grid.Rect{Min: grid.Point{Row: -3, Col: -10}, Max: grid.Point{Row: 2, Col: 5}}
+	if b := w.Bounds(); b != expected {
+		t.Errorf("Expected %v, got %v", expected, b)
+	}
+}
+
+func TestCrossings(t *testing.T) {
+	specs := []struct {
+		a, b     string
+		distance int
+		delay    int
+	}{
+		{"R8,U5,L5,D3", "U7,R6,D4,L4", 6, 30},
+		{"R75,D30,R83,U83,L12,D49,R71,U7,L72", "U62,R66,U55,R34,D71,R55,D58,R83", 159, 610},
+		{"R98,U47,R26,D63,R33,U87,L62,D20,R33,U53,R51", "U98,R91,D20,R16,D67,R40,U7,R15,U6,R7", 135, 410},
+		// Crossings on the row and column of the origin count too.
+		{"R5,U2", "U2,R5,D4", 5, 14},
+		// Running along each other, every shared point crosses.
+		{"R10", "U1,R3,D1,R4", 3, 8},
+	}
+
+	for _, spec := range specs {
+		crossings := Crossings(mustParse(t, spec.a), mustParse(t, spec.b))
+		closest, ok := Closest(crossings)
+		if !ok {
+			t.Errorf("%s and %s: expected crossings", spec.a, spec.b)
+			continue
+		}
+		if closest.Distance() != spec.distance {
+			t.Errorf("%s and %s: expected distance %d, got %d", spec.a, spec.b, spec.distance, closest.Distance())
+		}
+		if quickest, _ := Quickest(crossings); quickest.Delay() != spec.delay {
+			t.Errorf("%s and %s: expected delay %d, got %d", spec.a, spec.b, spec.delay, quickest.Delay())
+		}
+	}
+}
+
+func TestCollinear(t *testing.T) {
+	crossings := Crossings(mustParse(t, "R10"), mustParse(t, "U1,R3,D1,R4"))
+	if len(crossings) != 5 {
+		t.Fatalf("Expected 5 crossings, got %v", crossings)
+	}
+	for i, c := range crossings {
+		col := i + 3
+		if c.Point != (grid.Point{Row: 0, Col: col}) || c.Steps != [2]int{col, col + 2} {
+			t.Errorf("Expected col %d reached after %d and %d steps, got %+v", col, col, col+2, c)
+		}
+	}
+}
+
+func TestFirstVisit(t *testing.T) {
+	// b reaches (-1,1) after 2 steps and again after 8.
+	crossings := Crossings(mustParse(t, "R1,U1"), mustParse(t, "U1,R2,D2,L1,U3"))
+	if len(crossings) != 2 || crossings[1].Point != (grid.Point{Row: -1, Col: 1}) {
+		t.Fatalf("Expected crossings at (0,1) and (-1,1), got %v", crossings)
+	}
+	if crossings[1].Steps != [2]int{2, 2} {
+		t.Errorf("Expected steps [2 2], got %v", crossings[1].Steps)
+	}
+}
+
+func TestLongWires(t *testing.T) {
+	a := mustParse(t, "R5000000,U5000000")
+	b := mustParse(t, "U4000000,R6000000")
+	crossings := Crossings(a, b)
+	if len(crossings) != 1 || crossings[0].Point != (grid.Point{Row: -4000000, Col: 5000000}) {
+		t.Fatalf("Expected one crossing at (-4000000,5000000), got %v", crossings)
+	}
+	if crossings[0].Delay() != 18000000 {
+		t.Errorf("Expected delay 18000000, got %d", crossings[0].Delay())
+	}
+}
+
+func TestStepsTo(t *testing.T) {
+	w := mustParse(t, "U1,R2,D2,L1,U3")
+	specs := []struct {
+		p      grid.Point
+		visits []int
+	}{
+		{grid.Point{}, []int{0}},
+		{grid.Point{Row: -1, Col: 1}, []int{2, 8}},
+		{grid.Point{Row: 1, Col: 2}, []int{5}},
+		{grid.Point{Row: 5, Col: 5}, nil},
+	}
+	for _, spec := range specs {
+		if visits := w.Visits(spec.p); !reflect.DeepEqual(visits, spec.visits) {
+			t.Errorf("%v: expected visits %v, got %v", spec.p, spec.visits, visits)
+		}
+		steps, ok := w.StepsTo(spec.p)
+		if ok != (spec.visits != nil) || (ok && steps != spec.visits[0]) {
+			t.Errorf("%v: expected first visit %v, got %d, %t", spec.p, spec.visits, steps, ok)
+		}
+	}
+}
+
+func TestSelfCrossings(t *testing.T) {
+	specs := []struct {
+		path     string
+		expected []SelfCrossing
+	}{
+		{"R2,U2,L2", nil},
+		{"U1,R2,D2,L1,U3", []SelfCrossing{{grid.Point{Row: -1, Col: 1}, []int{2, 8}}}},
+		// Doubling back revisits every cell on the way, and the origin.
+		{"R2,L3", []SelfCrossing{
+			{grid.Point{Row: 0, Col: 0}, []int{0, 4}},
+			{grid.Point{Row: 0, Col: 1}, []int{1, 3}},
+		}},
+		{"R1,U1,L1,D1,R1", []SelfCrossing{
+			{grid.Point{Row: 0, Col: 0}, []int{0, 4}},
+			{grid.Point{Row: 0, Col: 1}, []int{1, 5}},
+		}},
+	}
+	for _, spec := range specs {
+		crossings := SelfCrossings(mustParse(t, spec.path))
+		if len(crossings) == 0 && len(spec.expected) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(crossings, spec.expected) {
+			t.Errorf("%s: expected %v, got %v", spec.path, spec.expected, crossings)
+		}
+	}
+}
+
+func TestCrossingsSkipOnlyOrigin(t *testing.T) {
+	crossings := Crossings(mustParse(t, "R2,L4"), mustParse(t, "L2,R4"))
+	var points []grid.Point
+	for _, c := range crossings {
+		points = append(points, c.Point)
+	}
+	expected := []grid.Point{{Row: 0, Col: -1}, {Row: 0, Col: 1}, {Row: 0, Col: -2}, {Row: 0, Col: 2}}
+	if !reflect.DeepEqual(points, expected) {
+		t.Errorf("Expected %v, got %v", expected, points)
+	}
+	if crossings[0].Steps != [2]int{5, 1} {
+		t.Errorf("Expected (0,-1) first reached after 5 and 1 steps, got %v", crossings[0].Steps)
+	}
+}